@@ -17,6 +17,9 @@ var (
 	msg  = flag.String("msg", "PING", "message you want to send")
 	room = flag.Uint64("room", 1, "room id")
 	id   = flag.Uint64("id", 1, "my id")
+
+	token     = flag.String("token", "", "token(spectator token if -spectator)")
+	spectator = flag.Bool("spectator", false, "connect as spectator")
 )
 
 func main() {
@@ -67,8 +70,10 @@ func main() {
 
 	// connect
 	if _, e := c.Write(pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), &pb.C2S_ConnectMsg{
-		PlayerID: proto.Uint64(*id),
-		BattleID: proto.Uint64(*room),
+		PlayerID:  proto.Uint64(*id),
+		BattleID:  proto.Uint64(*room),
+		Token:     proto.String(*token),
+		Spectator: proto.Bool(*spectator),
 	}).Serialize()); nil != e {
 		panic(fmt.Sprintf("write error:%s", e.Error()))
 	}
@@ -2,12 +2,14 @@ package api
 
 import (
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
 	_ "net/http/pprof"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/byebyebruce/lockstepserver/logic"
 )
@@ -28,6 +30,11 @@ func NewWebAPI(addr string, m *logic.RoomManager) *WebAPI {
 
 	http.HandleFunc("/", r.index)
 	http.HandleFunc("/create", r.createRoom)
+	http.HandleFunc("/room", r.roomInfo)
+	http.HandleFunc("/pause", r.pauseRoom)
+	http.HandleFunc("/player", r.player)
+	http.HandleFunc("/result", r.result)
+	http.HandleFunc("/kick", r.kick)
 
 	go func() {
 		fmt.Println("web api listen on", addr)
@@ -66,6 +73,7 @@ func (h *WebAPI) createRoom(w http.ResponseWriter, r *http.Request) {
 
 	roomStr := query.Get("room")
 	roomID, _ := strconv.ParseUint(roomStr, 10, 64)
+	typeID, _ := strconv.ParseInt(query.Get("type"), 10, 32)
 
 	ps := make([]uint64, 0, 10)
 
@@ -81,11 +89,135 @@ func (h *WebAPI) createRoom(w http.ResponseWriter, r *http.Request) {
 
 	}
 
-	room, err := h.m.CreateRoom(roomID, 0, ps, 0, "test")
+	room, err := h.m.CreateRoom(roomID, int32(typeID), ps, 0, "test")
 	if nil != err {
 		ret = err.Error()
 	} else {
-		ret = fmt.Sprintf("room.ID=[%d] room.Secret=[%s] room.Time=[%d], room.Member=[%v]", room.ID(), room.SecretKey(), room.TimeStamp(), members)
+		ret = fmt.Sprintf("room.ID=[%d] room.Secret=[%s] room.Time=[%d], room.Member=[%v] room.SpectatorToken=[%s]", room.ID(), room.SecretKey(), room.TimeStamp(), members, room.SpectatorToken())
 	}
 
 }
+
+func (h *WebAPI) roomInfo(w http.ResponseWriter, r *http.Request) {
+
+	roomID, _ := strconv.ParseUint(r.URL.Query().Get("room"), 10, 64)
+
+	room := h.m.GetRoom(roomID)
+	if nil == room {
+		http.Error(w, fmt.Sprintf("room[%d] not found", roomID), http.StatusNotFound)
+		return
+	}
+
+	info, ok := room.Info()
+	if !ok {
+		http.Error(w, fmt.Sprintf("room[%d] is closed", roomID), http.StatusGone)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+func (h *WebAPI) pauseRoom(w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+	roomID, _ := strconv.ParseUint(query.Get("room"), 10, 64)
+
+	room := h.m.GetRoom(roomID)
+	if nil == room {
+		http.Error(w, fmt.Sprintf("room[%d] not found", roomID), http.StatusNotFound)
+		return
+	}
+
+	ok := false
+	if len(query.Get("resume")) > 0 {
+		ok = room.Resume()
+	} else {
+		ok = room.Pause()
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("room[%d] state error", roomID), http.StatusConflict)
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+func (h *WebAPI) player(w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+	roomID, _ := strconv.ParseUint(query.Get("room"), 10, 64)
+
+	room := h.m.GetRoom(roomID)
+	if nil == room {
+		http.Error(w, fmt.Sprintf("room[%d] not found", roomID), http.StatusNotFound)
+		return
+	}
+
+	if add := query.Get("add"); len(add) > 0 {
+		id, _ := strconv.ParseUint(add, 10, 64)
+		seat, err := room.AddPlayer(id)
+		if nil != err {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.Write([]byte(fmt.Sprintf("player[%d] seat[%d]", id, seat)))
+		return
+	}
+
+	if remove := query.Get("remove"); len(remove) > 0 {
+		id, _ := strconv.ParseUint(remove, 10, 64)
+		if err := room.RemovePlayer(id); nil != err {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.Write([]byte("ok"))
+		return
+	}
+
+	if team := query.Get("team"); len(team) > 0 {
+		id, _ := strconv.ParseUint(query.Get("id"), 10, 64)
+		t, _ := strconv.ParseInt(team, 10, 32)
+		if err := room.SetTeam(id, int32(t)); nil != err {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.Write([]byte("ok"))
+		return
+	}
+
+	http.Error(w, "add, remove or team is required", http.StatusBadRequest)
+}
+
+func (h *WebAPI) result(w http.ResponseWriter, r *http.Request) {
+
+	roomID, _ := strconv.ParseUint(r.URL.Query().Get("room"), 10, 64)
+
+	report := h.m.GetReport(roomID)
+	if nil == report {
+		http.Error(w, fmt.Sprintf("room[%d] result not found", roomID), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *WebAPI) kick(w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+	roomID, _ := strconv.ParseUint(query.Get("room"), 10, 64)
+
+	room := h.m.GetRoom(roomID)
+	if nil == room {
+		http.Error(w, fmt.Sprintf("room[%d] not found", roomID), http.StatusNotFound)
+		return
+	}
+
+	id, _ := strconv.ParseUint(query.Get("id"), 10, 64)
+	cooldown, _ := strconv.ParseInt(query.Get("cooldown"), 10, 64)
+	if err := room.Kick(id, query.Get("reason"), time.Duration(cooldown)*time.Second); nil != err {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.Write([]byte("ok"))
+}
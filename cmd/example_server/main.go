@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/byebyebruce/lockstepserver/cmd/example_server/api"
+	"github.com/byebyebruce/lockstepserver/logic/game"
+	"github.com/byebyebruce/lockstepserver/logic/room"
 	"github.com/byebyebruce/lockstepserver/pkg/log4gox"
 	"github.com/byebyebruce/lockstepserver/server"
 
@@ -19,6 +21,14 @@ var (
 	httpAddress = flag.String("web", ":80", "web listen address")
 	udpAddress  = flag.String("udp", ":10086", "udp listen address(':10086' means localhost:10086)")
 	debugLog    = flag.Bool("log", true, "debug log")
+	resultDir   = flag.String("result_dir", "", "write room results to this dir")
+	resultURL   = flag.String("result_url", "", "post room results to this url")
+	recoverRoom = flag.Bool("recover", false, "recover from room panics instead of crashing the server")
+	crashDir    = flag.String("crash_dir", "", "write room crash dumps to this dir(with -recover)")
+	roomTypes   = flag.String("room_types", "", "room types config file(json)")
+	checkpoint  = flag.String("checkpoint_dir", "", "save running rooms to this dir and restore them on startup")
+	cpInterval  = flag.Duration("checkpoint_interval", time.Second*5, "checkpoint interval")
+	workers     = flag.Int("workers", 0, "drive rooms with a shared scheduler of this many workers(0 means one goroutine per room)")
 )
 
 func main() {
@@ -31,6 +41,43 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+
+	sinks := room.MultiSink{}
+	if len(*resultDir) > 0 {
+		sinks = append(sinks, &room.FileSink{Dir: *resultDir})
+	}
+	if len(*resultURL) > 0 {
+		sinks = append(sinks, room.NewWebhookSink(*resultURL, time.Second*5))
+	}
+	s.RoomManager().SetResultSink(sinks)
+	s.RoomManager().RegisterNamedValidator("range", func(id uint64) game.InputValidator {
+		return &game.RangeValidator{MinX: -10000, MaxX: 10000, MinY: -10000, MaxY: 10000}
+	})
+	if len(*roomTypes) > 0 {
+		if err := s.RoomManager().LoadRoomTypes(*roomTypes); nil != err {
+			panic(err)
+		}
+	}
+	if *recoverRoom {
+		s.RoomManager().SetCrashConfig(&room.CrashConfig{
+			Recover:  *recoverRoom,
+			DumpDir:  *crashDir,
+			Messages: 256,
+			Frames:   300,
+		})
+	}
+	if *workers > 0 {
+		s.RoomManager().UseScheduler(*workers)
+	}
+	if len(*checkpoint) > 0 {
+		s.RoomManager().EnableCheckpoint(&room.DirCheckpointStore{Dir: *checkpoint}, *cpInterval)
+		n, err := s.RoomManager().RestoreRooms()
+		if nil != err {
+			panic(err)
+		}
+		l4g.Warn("[main] restored %d rooms", n)
+	}
+
 	_ = api.NewWebAPI(*httpAddress, s.RoomManager())
 
 	sigs := make(chan os.Signal, 1)
@@ -0,0 +1,144 @@
+package logic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/room"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// EnableCheckpoint 每隔interval给所有房间存一次档，停服时也存一次。要在RestoreRooms和创建房间之前调用
+func (m *RoomManager) EnableCheckpoint(store room.CheckpointStore, interval time.Duration) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	if nil != m.checkpoints {
+		return
+	}
+
+	m.checkpoints = store
+	m.cpExit = make(chan struct{})
+
+	m.cpWg.Add(1)
+	go m.checkpointLoop(store, interval, m.cpExit)
+}
+
+func (m *RoomManager) checkpointLoop(store room.CheckpointStore, interval time.Duration, exit chan struct{}) {
+	defer m.cpWg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-ticker.C:
+			m.saveCheckpoints(store)
+		}
+	}
+}
+
+// saveCheckpoints 给所有还没结束的房间存档，每个房间只存上次存档之后的帧
+func (m *RoomManager) saveCheckpoints(store room.CheckpointStore) {
+	m.rw.RLock()
+	rooms := make([]*room.Room, 0, len(m.room))
+	for _, v := range m.room {
+		rooms = append(rooms, v)
+	}
+	m.rw.RUnlock()
+
+	for _, r := range rooms {
+		m.rw.RLock()
+		from := m.cpFrames[r.ID()]
+		m.rw.RUnlock()
+
+		cp, ok := r.Checkpoint(from)
+		if !ok {
+			continue
+		}
+		if err := store.Save(cp); nil != err {
+			l4g.Error("[room(%d)] save checkpoint error:[%s]", r.ID(), err.Error())
+			continue
+		}
+
+		m.rw.Lock()
+		if _, ok := m.room[r.ID()]; ok {
+			m.cpFrames[r.ID()] = cp.Game.FrameCount
+		}
+		m.rw.Unlock()
+		// 存档的时候刚好结束了
+		if r.IsOver() {
+			store.Delete(r.ID())
+		}
+	}
+}
+
+// stopCheckpoint 停掉定时存档，再存最后一次
+func (m *RoomManager) stopCheckpoint() {
+	m.rw.Lock()
+	store, exit := m.checkpoints, m.cpExit
+	m.cpExit = nil
+	m.rw.Unlock()
+
+	if nil == exit {
+		return
+	}
+
+	close(exit)
+	m.cpWg.Wait()
+	m.saveCheckpoints(store)
+}
+
+// RestoreRooms 从存档恢复上次停服时还在跑的房间，玩家用正常的连接流程重连。返回恢复的房间数
+func (m *RoomManager) RestoreRooms() (int, error) {
+	m.rw.RLock()
+	store := m.checkpoints
+	m.rw.RUnlock()
+
+	if nil == store {
+		return 0, fmt.Errorf("checkpoint is not enabled")
+	}
+
+	cps, err := store.Load()
+	if nil != err {
+		return 0, err
+	}
+
+	n := 0
+	for _, cp := range cps {
+		if err := m.restoreRoom(cp); nil != err {
+			l4g.Error("[room(%d)] restore error:[%s]", cp.RoomID, err.Error())
+			store.Delete(cp.RoomID)
+			continue
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+func (m *RoomManager) restoreRoom(cp *room.Checkpoint) error {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	if _, ok := m.room[cp.RoomID]; ok {
+		return fmt.Errorf("room id[%d] exists", cp.RoomID)
+	}
+
+	cfg, err := m.typeConfig(cp.TypeID)
+	if nil != err {
+		return err
+	}
+
+	r, err := room.RestoreRoom(cp, cfg)
+	if nil != err {
+		return err
+	}
+	m.startRoom(r)
+	m.cpFrames[cp.RoomID] = cp.Game.FrameCount
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+	"github.com/byebyebruce/lockstepserver/logic/room"
+)
+
+func Test_Checkpoint(t *testing.T) {
+	store := &room.DirCheckpointStore{Dir: t.TempDir()}
+
+	m := NewRoomManager()
+	m.EnableCheckpoint(store, time.Hour)
+	r, err := m.CreateRoom(1, 0, []uint64{1, 2}, 7, "test")
+	if nil != err {
+		t.Fatal(err)
+	}
+	token := r.SpectatorToken()
+
+	// 停服时存档
+	m.Stop()
+	if files, _ := filepath.Glob(filepath.Join(store.Dir, "room_*.json")); len(files) != 1 {
+		t.Fatalf("should save one checkpoint, got %v", files)
+	}
+
+	m = NewRoomManager()
+	m.EnableCheckpoint(store, time.Hour)
+	n, err := m.RestoreRooms()
+	if nil != err || n != 1 {
+		t.Fatalf("restore rooms n=%d err=%v", n, err)
+	}
+
+	r = m.GetRoom(1)
+	if nil == r || !r.HasPlayer(2) || r.SpectatorToken() != token {
+		t.Fatal("room not restored")
+	}
+	if _, ok := r.Info(); !ok {
+		t.Error("restored room should be running")
+	}
+	m.Stop()
+}
+
+func Test_CheckpointTimeout(t *testing.T) {
+	store := &room.DirCheckpointStore{Dir: t.TempDir()}
+
+	cfg := game.DefaultConfig()
+	cfg.RoomTimeout = time.Millisecond * 50
+	m := NewRoomManager()
+	m.SetGameConfig(cfg)
+	m.EnableCheckpoint(store, time.Millisecond*10)
+	if _, err := m.CreateRoom(1, 0, []uint64{1, 2}, 7, "test"); nil != err {
+		t.Fatal(err)
+	}
+
+	// 超时结束的房间删掉存档
+	for i := 0; nil != m.GetRoom(1); i++ {
+		if i > 100 {
+			t.Fatal("room should time out")
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+	m.Stop()
+	if files, _ := filepath.Glob(filepath.Join(store.Dir, "room_*")); len(files) != 0 {
+		t.Fatalf("checkpoint should be deleted, got %v", files)
+	}
+
+	m = NewRoomManager()
+	m.EnableCheckpoint(store, time.Hour)
+	if n, err := m.RestoreRooms(); nil != err || n != 0 {
+		t.Fatalf("should restore nothing n=%d err=%v", n, err)
+	}
+	m.Stop()
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// ChatRecord 聊天记录
+type ChatRecord struct {
+	TimeStampMs int64
+	ID          uint64
+	Seat        int32
+	Scope       pb.SCOPE
+	Text        string `json:",omitempty"`
+	Emote       int32  `json:",omitempty"`
+}
+
+// SetTeam 设置玩家的队伍，没设置的都在队伍0
+func (g *Game) SetTeam(id uint64, team int32) error {
+	p, ok := g.players[id]
+	if !ok {
+		return fmt.Errorf("player[%d] not found", id)
+	}
+
+	p.team = team
+	return nil
+}
+
+// ChatLog 聊天记录(配置了ChatRecord才有)
+func (g *Game) ChatLog() []ChatRecord {
+	return g.chatLog
+}
+
+// doChat 聊天消息不进帧，直接转发给接收范围内的玩家(包括自己)
+func (g *Game) doChat(p *Player, msg *pb_packet.Packet) {
+	m := &pb.C2S_ChatMsg{}
+	if err := msg.Unmarshal(m); nil != err {
+		l4g.Error("[game(%d)] doChat player[%d] UnmarshalPB error:[%s]", g.id, p.id, err.Error())
+		return
+	}
+
+	now := time.Now()
+	text := m.GetText()
+
+	code := pb.ERRORCODE_ERR_Ok
+	if g.cfg.ChatRate > 0 && !p.chatLimit.allow(now, g.cfg.ChatRate) {
+		code = pb.ERRORCODE_ERR_RateLimit
+	} else if g.cfg.ChatMaxLen > 0 && len(text) > g.cfg.ChatMaxLen {
+		code = pb.ERRORCODE_ERR_TooLong
+	} else if nil != g.cfg.ChatFilter && len(text) > 0 {
+		var ok bool
+		if text, ok = g.cfg.ChatFilter(p.id, text); !ok {
+			code = pb.ERRORCODE_ERR_Filtered
+		}
+	}
+
+	if pb.ERRORCODE_ERR_Ok != code {
+		l4g.Warn("[game(%d)] player[%d] chat rejected[%s]", g.id, p.id, code)
+		p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Chat), &pb.S2C_ChatMsg{
+			ErrorCode: code.Enum(),
+		}))
+		return
+	}
+
+	ret := pb_packet.NewPacket(uint8(pb.ID_MSG_Chat), &pb.S2C_ChatMsg{
+		ErrorCode:   pb.ERRORCODE_ERR_Ok.Enum(),
+		Id:          proto.Uint64(p.id),
+		Seat:        proto.Int32(p.idx),
+		Scope:       m.GetScope().Enum(),
+		Text:        proto.String(text),
+		Emote:       proto.Int32(m.GetEmote()),
+		TimeStampMs: proto.Int64(now.UnixMilli()),
+	})
+	for _, v := range g.players {
+		if pb.SCOPE_SCOPE_Team == m.GetScope() && v.team != p.team {
+			continue
+		}
+		v.SendMessage(ret)
+	}
+
+	if g.cfg.ChatRecord {
+		g.chatLog = append(g.chatLog, ChatRecord{
+			TimeStampMs: now.UnixMilli(),
+			ID:          p.id,
+			Seat:        p.idx,
+			Scope:       m.GetScope(),
+			Text:        text,
+			Emote:       m.GetEmote(),
+		})
+	}
+}
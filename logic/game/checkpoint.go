@@ -0,0 +1,252 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// PlayerCheckpoint 玩家需要恢复的状态，连接相关的都不存
+type PlayerCheckpoint struct {
+	ID          uint64
+	Seat        int32
+	Team        int32
+	PauseCount  uint32
+	Desync      uint32
+	Kicked      bool
+	KickedUntil time.Time
+	Surrendered bool
+	Left        bool
+}
+
+// SnapshotCheckpoint 达成一致的快照
+type SnapshotCheckpoint struct {
+	FrameID uint32
+	Hash    uint64
+	Data    []byte
+}
+
+// Checkpoint 游戏存档，还没结束的帧和正在上传的快照不存
+type Checkpoint struct {
+	State       GameState
+	RandomSeed  int32
+	StartTime   int64
+	StartTimeMs int64
+	SavedAtMs   int64
+	NextSeat    int32
+	FrameCount  uint32
+	PausedTime  time.Duration
+	Players     []PlayerCheckpoint
+	FromFrame   uint32                  `json:"-"` // Frames从哪一帧开始
+	Frames      []*pb.FrameData         `json:"-"` // [FromFrame, FrameCount)之间结束的非空帧，由存储追加保存
+	Results     map[uint64]*MatchResult `json:",omitempty"`
+	Chat        []ChatRecord            `json:",omitempty"`
+	Snapshot    *SnapshotCheckpoint     `json:",omitempty"`
+}
+
+// Checkpoint 生成存档，帧只带from之后的，之前的上次已经存过了
+func (g *Game) Checkpoint(from uint32) *Checkpoint {
+	cp := &Checkpoint{
+		State:       g.State,
+		RandomSeed:  g.randomSeed,
+		StartTime:   g.startTime,
+		StartTimeMs: g.startTimeMs,
+		SavedAtMs:   time.Now().UnixMilli(),
+		NextSeat:    g.nextSeat,
+		FrameCount:  g.logic.getFrameCount(),
+		PausedTime:  g.PausedTime(),
+		Results:     make(map[uint64]*MatchResult, len(g.result)),
+		Chat:        append([]ChatRecord(nil), g.chatLog...),
+	}
+
+	for _, v := range g.players {
+		cp.Players = append(cp.Players, PlayerCheckpoint{
+			ID:          v.id,
+			Seat:        v.idx,
+			Team:        v.team,
+			PauseCount:  v.pauseCount,
+			Desync:      v.desyncCount,
+			Kicked:      v.kicked,
+			KickedUntil: v.kickedUntil,
+			Surrendered: v.surrendered,
+			Left:        v.left,
+		})
+	}
+	sort.Slice(cp.Players, func(i, j int) bool { return cp.Players[i].Seat < cp.Players[j].Seat })
+
+	if from > cp.FrameCount {
+		from = cp.FrameCount
+	}
+	cp.FromFrame = from
+	cp.Frames = g.logic.getRangeFrames(from, cp.FrameCount)
+
+	for k, v := range g.result {
+		cp.Results[k] = v
+	}
+
+	if nil != g.snapshot {
+		cp.Snapshot = &SnapshotCheckpoint{
+			FrameID: g.snapshot.frameID,
+			Hash:    g.snapshot.hash,
+			Data:    g.snapshot.data,
+		}
+	}
+
+	return cp
+}
+
+// RestoreGame 从存档恢复游戏，cp.Frames要是从第0帧开始的所有非空帧。所有玩家都是掉线状态，等他们走正常的连接流程重连。
+// 存档之后到重启之间的帧会丢失，开始时间按停服的时间往后推
+func RestoreGame(id uint64, cp *Checkpoint, cfg *Config, listener gameListener) (*Game, error) {
+	if k_Ready != cp.State && k_Gaming != cp.State && k_Paused != cp.State {
+		return nil, fmt.Errorf("game state[%d] can not restore", cp.State)
+	}
+
+	g := NewGame(id, nil, cp.RandomSeed, cfg, listener)
+
+	now := g.now
+	down := now.UnixMilli() - cp.SavedAtMs
+	if down < 0 {
+		down = 0
+	}
+
+	g.State = cp.State
+	g.startTimeMs = cp.StartTimeMs + down
+	g.startTime = cp.StartTime + down/1000
+	g.nextSeat = cp.NextSeat
+	g.pausedTime = cp.PausedTime
+	g.pausedAt = now
+	g.turnStart = now
+	g.chatLog = cp.Chat
+	g.restoreUntil = now.Add(g.cfg.RestoreGrace)
+
+	for _, v := range cp.Players {
+		p := NewPlayer(v.ID, v.Seat)
+		p.team = v.Team
+		p.pauseCount = v.PauseCount
+		p.desyncCount = v.Desync
+		p.kicked = v.Kicked
+		p.kickedUntil = v.KickedUntil
+		p.surrendered = v.Surrendered
+		p.left = v.Left
+		// 重连回来通知其他人
+		p.dropped = g.isPlaying()
+		g.players[v.ID] = p
+	}
+
+	for k, v := range cp.Results {
+		g.result[k] = v
+	}
+
+	if nil != cp.Snapshot {
+		g.snapshot = &snapshot{
+			frameID: cp.Snapshot.FrameID,
+			hash:    cp.Snapshot.Hash,
+			data:    cp.Snapshot.Data,
+			players: make(map[uint64]bool),
+		}
+	}
+
+	if err := g.logic.restore(cp.Frames, cp.FrameCount); nil != err {
+		g.logic.close()
+		return nil, err
+	}
+	g.clientFrameCount = cp.FrameCount
+
+	if g.isPlaying() {
+		g.replaySimulation()
+	}
+
+	l4g.Warn("[game(%d)] restored state[%d] frame[%d] players[%d] down=[%dms]", id, g.State, cp.FrameCount, len(g.players), down)
+
+	return g, nil
+}
+
+// replaySimulation 重新创建权威模拟，把所有已经结束的帧跑一遍
+func (g *Game) replaySimulation() {
+	g.newSimulation()
+	if nil == g.sim {
+		return
+	}
+
+	frames := g.logic.getRangeFrames(0, g.logic.getFrameCount())
+	for idx, i := uint32(0), 0; idx < g.logic.getFrameCount(); idx++ {
+		f := &pb.FrameData{FrameID: proto.Uint32(idx)}
+		if i < len(frames) && frames[i].GetFrameID() == idx {
+			f = frames[i]
+			i++
+		}
+		// 还没往异步模拟发过帧，这里直接同步执行
+		g.onSimState(g.sim.step(f))
+	}
+}
+
+// restore 把存档的帧按顺序放回存储，中间的空帧补上
+func (l *lockstep) restore(frames []*pb.FrameData, count uint32) error {
+	i := 0
+	for idx := uint32(0); idx < count; idx++ {
+		f := &pb.FrameData{FrameID: proto.Uint32(idx)}
+		if i < len(frames) && frames[i].GetFrameID() == idx {
+			f = frames[i]
+			i++
+		}
+		if err := l.store.Append(f); nil != err {
+			return fmt.Errorf("restore frame[%d] error: %s", idx, err.Error())
+		}
+	}
+	l.frameCount = count
+
+	return nil
+}
+
+// FrameLog 存档用的只追加帧文件，格式和落盘的segment一样。每次存档只追加新结束的帧
+type FrameLog struct {
+	seg *segment
+}
+
+// OpenFrameLog 打开帧文件，没有就创建
+func OpenFrameLog(path string) (*FrameLog, error) {
+	s, err := loadSegment(path)
+	if nil != err {
+		return nil, err
+	}
+	return &FrameLog{seg: s}, nil
+}
+
+// Append 追加from开始的帧，from之后已经写过的(上次存档没成功)先删掉
+func (l *FrameLog) Append(from uint32, frames []*pb.FrameData) error {
+	if err := l.seg.truncate(from); nil != err {
+		return err
+	}
+	for _, f := range frames {
+		if err := l.seg.append(f); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+// Frames 读取to之前的所有帧
+func (l *FrameLog) Frames(to uint32) ([]*pb.FrameData, error) {
+	var ret []*pb.FrameData
+	err := l.seg.rangeFrames(0, to, func(f *pb.FrameData) bool {
+		ret = append(ret, f)
+		return true
+	})
+	return ret, err
+}
+
+// Close 关闭文件
+func (l *FrameLog) Close() error {
+	return l.seg.file.Close()
+}
+
+// Remove 关闭并删除文件
+func (l *FrameLog) Remove() error {
+	return l.seg.remove()
+}
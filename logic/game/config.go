@@ -0,0 +1,171 @@
+package game
+
+import (
+	"time"
+)
+
+const (
+	kDefaultTickRate   = 30 // 默认每秒帧数
+	kMinSnapshotQuorum = 2  // 快照至少要两个玩家一致，一个人说了不算
+)
+
+// LateInputPolicy 迟到输入(目标帧已经广播出去)的处理方式
+type LateInputPolicy int
+
+const (
+	LateInputBump   LateInputPolicy = 0 // 顺延到当前帧
+	LateInputReject LateInputPolicy = 1 // 直接丢弃
+	LateInputReport LateInputPolicy = 2 // 丢弃并通知客户端
+)
+
+// Config 游戏配置
+type Config struct {
+	TickRate    uint32        // 每秒多少帧
+	MaxPlayers  uint32        // 最多多少个座位(0不限制)
+	MaxDuration time.Duration // 游戏最长时间，不算暂停(0按MaxGameFrame)
+	RoomTimeout time.Duration // 房间最长存在时间(墙钟)，0按准备、倒计时和最长时间算，回合制模式下0表示不限制
+
+	ReadyTimeout    time.Duration // 准备阶段最长时间，超时后准备好的人数够就开始，不够就结束
+	MinReadyPlayers uint32        // 最少多少人准备好才能开始
+	LoadingPolicy   LoadingPolicy // 开始时还没准备好的玩家怎么处理
+	HostStart       bool          // 由房主(座位号最小的在场玩家)发MSG_Start开始
+	StartCountdown  time.Duration // 开始前的倒计时(0不倒计时)
+
+	LockstepMode      LockstepMode      // 帧同步模式
+	TurnTimeout       time.Duration     // 回合制模式下一帧最多等多久(0一直等)
+	TurnTimeoutPolicy TurnTimeoutPolicy // 回合制模式下超时的处理方式
+	MaxTurns          uint32            // 回合制模式下最多多少回合(0按MaxGameFrame)
+
+	InputDelay      uint32          // 服务端输入延迟(帧)，输入的目标帧=客户端帧ID(没填就是当前帧)+InputDelay
+	MaxInputAhead   uint32          // 输入的目标帧最多可以超前当前帧多少帧
+	LateInputPolicy LateInputPolicy // 迟到输入的处理方式
+
+	FrameMemoryWindow uint32                              // 内存里最多保留最近多少帧，更早的写到FrameSpillDir
+	FrameSpillDir     string                              // 帧数据落盘目录，为空时所有帧都放内存
+	NewFrameStore     func(id uint64) (FrameStore, error) // 自定义帧存储，不为空时优先使用
+
+	SlowRTT            time.Duration // RTT超过这个值认为网络变差(0不检查)
+	SlowAckLag         uint32        // 已发送未确认的帧数超过这个值认为网络变差(0不检查)
+	SlowSendQueue      int           // 发送队列积压超过这个值认为网络变差(0不检查)
+	SlowHeartbeat      time.Duration // 这么久没收到心跳认为网络变差(0不检查)
+	NetRecoverTime     time.Duration // 指标正常持续这么久才认为恢复
+	DegradedSendFrames uint32        // 网络差的玩家攒够多少帧发一次
+
+	PauseMode        PauseMode     // 玩家暂停的方式
+	PauseBudget      uint32        // PauseByBudget模式下每个玩家能暂停几次
+	PauseVoteTimeout time.Duration // PauseByVote模式下一票的有效时间
+	MaxPauseTime     time.Duration // 单次暂停最长时间，超时自动恢复(0不限制)
+
+	MaxSpectators        uint32 // 每个房间最多多少观战者
+	SpectatorDelayFrames uint32 // 观战者延迟多少帧看到(防止通过观战作弊)
+
+	HostKick     bool          // 房主(座位号最小的在场玩家)可以踢人
+	KickCooldown time.Duration // 被踢的玩家多久之后可以重新进入(0不能再进入)
+
+	NewInputValidator  func(id uint64) InputValidator // 创建房间的输入校验(为空不校验)
+	MaxInputViolations uint32                         // 输入违规超过这么多次踢掉(0不踢)
+
+	NewSimulation   func(id uint64, seed int32, players []uint64) (Simulation, error) // 创建服务端权威模拟(为空不模拟)
+	SimulationAsync bool                                                              // 模拟在单独的goroutine里跑
+	SimHashWindow   uint32                                                            // 保留最近多少帧的状态hash用来校验
+
+	SnapshotQuorum  uint32 // 至少多少个玩家上传了相同帧和hash的快照才采用(最少2个)
+	SnapshotMaxSize uint32 // 快照最大大小(字节)
+
+	ChatRate   uint32                                      // 每个玩家每秒最多发几条聊天(0不限制)
+	ChatMaxLen int                                         // 聊天文字最大长度(字节，0不限制)
+	ChatFilter func(id uint64, text string) (string, bool) // 聊天过滤，可以改写文字，返回false丢弃
+	ChatRecord bool                                        // 是否记录聊天
+
+	RelayRate    uint32 // 每个玩家每秒最多转发几条消息(0不限制)
+	RelayMaxSize int    // 转发消息数据最大长度(字节，0不限制)
+
+	ResultPolicy ResultPolicy // 结果共识方式
+	ResultQuorum uint32       // ResultQuorum方式下至少多少个提交一致
+
+	RestoreGrace time.Duration // 从存档恢复之后等玩家重连的时间，这段时间里掉线的玩家不算离开
+}
+
+// MaxFrames 游戏最多多少帧
+func (c *Config) MaxFrames() uint32 {
+	// 回合制一帧就是一回合，和时间无关
+	if LockstepTurn == c.LockstepMode {
+		if 0 == c.MaxTurns {
+			return MaxGameFrame
+		}
+		return c.MaxTurns
+	}
+	if c.MaxDuration <= 0 || 0 == c.TickRate {
+		return MaxGameFrame
+	}
+	return uint32(c.MaxDuration * time.Duration(c.TickRate) / time.Second)
+}
+
+// TickInterval 每帧的时间
+func (c *Config) TickInterval() time.Duration {
+	rate := c.TickRate
+	if 0 == rate {
+		rate = kDefaultTickRate
+	}
+	return time.Second / time.Duration(rate)
+}
+
+// snapshotQuorum 采用快照需要的玩家数，配置小于2的按2算
+func (c *Config) snapshotQuorum() int {
+	if c.SnapshotQuorum < kMinSnapshotQuorum {
+		return kMinSnapshotQuorum
+	}
+	return int(c.SnapshotQuorum)
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		TickRate: kDefaultTickRate,
+
+		ReadyTimeout:    time.Second * 20,
+		MinReadyPlayers: 1,
+		LoadingPolicy:   LoadingLateJoin,
+
+		LockstepMode:      LockstepRelay,
+		TurnTimeout:       time.Second * 30,
+		TurnTimeoutPolicy: TurnTimeoutEvent,
+
+		InputDelay:      0,
+		MaxInputAhead:   30,
+		LateInputPolicy: LateInputBump,
+
+		FrameMemoryWindow: 30 * 60 * 5,
+
+		SlowRTT:            time.Millisecond * 300,
+		SlowAckLag:         30,
+		SlowSendQueue:      256,
+		SlowHeartbeat:      time.Second * 2,
+		NetRecoverTime:     time.Second * 3,
+		DegradedSendFrames: 10,
+
+		PauseMode:        PauseByBudget,
+		PauseBudget:      2,
+		PauseVoteTimeout: time.Second * 10,
+		MaxPauseTime:     time.Minute,
+
+		MaxSpectators:        16,
+		SpectatorDelayFrames: 30 * 10,
+
+		SimHashWindow: 30 * 60,
+
+		SnapshotQuorum:  kMinSnapshotQuorum,
+		SnapshotMaxSize: 1024 * 1024,
+
+		ChatRate:   3,
+		ChatMaxLen: 128,
+
+		RelayRate:    10,
+		RelayMaxSize: 512,
+
+		ResultPolicy: ResultMajority,
+		ResultQuorum: 2,
+
+		RestoreGrace: time.Second * 30,
+	}
+}
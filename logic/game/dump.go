@@ -0,0 +1,48 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+// DebugState 出错时用来复现的游戏现场
+type DebugState struct {
+	State      GameState
+	FrameCount uint32
+	RandomSeed int32
+	StartTime  int64
+	Players    []PlayerStats
+	Spectators int
+	Frames     []*pb.FrameData // 最近的非空帧
+	Pending    []*pb.FrameData // 还没结束的帧
+}
+
+// DebugState 获得游戏现场，frames为最多带多少帧历史
+func (g *Game) DebugState(frames uint32) *DebugState {
+	s := &DebugState{
+		State:      g.State,
+		FrameCount: g.logic.getFrameCount(),
+		RandomSeed: g.randomSeed,
+		StartTime:  g.startTime,
+		Players:    g.PlayerStats(),
+		Spectators: len(g.spectators),
+	}
+
+	from := uint32(0)
+	if s.FrameCount > frames {
+		from = s.FrameCount - frames
+	}
+	s.Frames = g.logic.getRangeFrames(from, s.FrameCount)
+
+	for idx, f := range g.logic.frames {
+		s.Pending = append(s.Pending, &pb.FrameData{
+			FrameID: proto.Uint32(idx),
+			Input:   f.cmds,
+		})
+	}
+	sort.Slice(s.Pending, func(i, j int) bool { return s.Pending[i].GetFrameID() < s.Pending[j].GetFrameID() })
+
+	return s
+}
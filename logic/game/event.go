@@ -0,0 +1,76 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// pushEvent 在当前帧插入系统事件，所有客户端在同一帧处理
+func (g *Game) pushEvent(p *Player, evt pb.EVENT) {
+	if !g.isPlaying() {
+		return
+	}
+
+	cmd := &pb.InputData{
+		Id:         proto.Uint64(p.id),
+		Roomseatid: proto.Int32(p.idx),
+		Event:      evt.Enum(),
+	}
+
+	idx := g.logic.getFrameCount()
+	if !g.logic.pushCmd(idx, cmd) {
+		l4g.Warn("[game(%d)] push event[%s] player[%d] frame[%d] failed", g.id, evt, p.id, idx)
+		return
+	}
+
+	// 下一帧强制广播
+	g.dirty = true
+	l4g.Info("[game(%d)] event[%s] player[%d] frame[%d]", g.id, evt, p.id, idx)
+}
+
+// AddPlayer 增加一个座位，游戏中加入的玩家通过重连流程拿到所有帧
+func (g *Game) AddPlayer(id uint64) (int32, error) {
+	if _, ok := g.players[id]; ok {
+		return 0, fmt.Errorf("player[%d] exists", id)
+	}
+
+	if k_Ready != g.State && !g.isPlaying() {
+		return 0, fmt.Errorf("game state[%d] error", g.State)
+	}
+
+	if g.cfg.MaxPlayers > 0 && len(g.players) >= int(g.cfg.MaxPlayers) {
+		return 0, fmt.Errorf("players are full")
+	}
+
+	// 座位号不复用
+	g.nextSeat++
+	p := NewPlayer(id, g.nextSeat)
+	g.players[id] = p
+
+	g.pushEvent(p, pb.EVENT_EVT_Join)
+	l4g.Warn("[game(%d)] add player[%d] seat[%d] frame[%d]", g.id, id, p.idx, g.logic.getFrameCount())
+
+	return p.idx, nil
+}
+
+// RemovePlayer 删除一个座位
+func (g *Game) RemovePlayer(id uint64) error {
+	p, ok := g.players[id]
+	if !ok {
+		return fmt.Errorf("player[%d] not found", id)
+	}
+
+	g.pushEvent(p, pb.EVENT_EVT_Remove)
+
+	delete(g.players, id)
+	delete(g.result, id)
+	p.Cleanup()
+
+	l4g.Warn("[game(%d)] remove player[%d] seat[%d] frame[%d]", g.id, id, p.idx, g.logic.getFrameCount())
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+)
+
+// FrameStore 已经结束的帧的存储
+type FrameStore interface {
+	// Append 追加一帧，帧ID必须连续递增，空帧也要追加
+	Append(f *pb.FrameData) error
+	// Range 按顺序遍历[from, to)之间的非空帧，fn返回false停止遍历
+	Range(from, to uint32, fn func(f *pb.FrameData) bool) error
+	// Close 释放资源
+	Close() error
+}
+
+// newFrameStore 根据配置构造帧存储
+func newFrameStore(id uint64, cfg *Config) (FrameStore, error) {
+	if nil != cfg.NewFrameStore {
+		return cfg.NewFrameStore(id)
+	}
+	if len(cfg.FrameSpillDir) == 0 || 0 == cfg.FrameMemoryWindow {
+		return newMemFrameStore(), nil
+	}
+	return newSpillFrameStore(filepath.Join(cfg.FrameSpillDir, fmt.Sprintf("frames_%d.seg", id)), cfg.FrameMemoryWindow)
+}
+
+// memFrameStore 全部放在内存里，只存非空帧
+type memFrameStore struct {
+	frames []*pb.FrameData
+}
+
+func newMemFrameStore() *memFrameStore {
+	return &memFrameStore{}
+}
+
+func (s *memFrameStore) Append(f *pb.FrameData) error {
+	if len(f.Input) > 0 {
+		s.frames = append(s.frames, f)
+	}
+	return nil
+}
+
+func (s *memFrameStore) Range(from, to uint32, fn func(f *pb.FrameData) bool) error {
+	i := sort.Search(len(s.frames), func(i int) bool { return s.frames[i].GetFrameID() >= from })
+	for ; i < len(s.frames) && s.frames[i].GetFrameID() < to; i++ {
+		if !fn(s.frames[i]) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *memFrameStore) Close() error {
+	s.frames = nil
+	return nil
+}
+
+// spillFrameStore 内存里用环形数组保存最近的window帧，更早的帧写到磁盘
+type spillFrameStore struct {
+	ring  []*pb.FrameData // 第i帧放在ring[i%len(ring)]，空帧为nil
+	base  uint32          // 内存里最早的帧ID
+	count uint32          // 已经追加的帧数
+	seg   *segment
+}
+
+func newSpillFrameStore(path string, window uint32) (*spillFrameStore, error) {
+	seg, err := openSegment(path)
+	if nil != err {
+		return nil, err
+	}
+	return &spillFrameStore{
+		ring: make([]*pb.FrameData, window),
+		seg:  seg,
+	}, nil
+}
+
+func (s *spillFrameStore) Append(f *pb.FrameData) error {
+	if f.GetFrameID() != s.count {
+		return fmt.Errorf("frame id[%d] should be [%d]", f.GetFrameID(), s.count)
+	}
+
+	w := uint32(len(s.ring))
+	slot := s.count % w
+	// 环满了，把最老的一帧挪到磁盘
+	if s.count-s.base >= w {
+		if old := s.ring[slot]; nil != old {
+			if err := s.seg.append(old); nil != err {
+				return err
+			}
+		}
+		s.base++
+	}
+
+	if len(f.Input) > 0 {
+		s.ring[slot] = f
+	} else {
+		s.ring[slot] = nil
+	}
+	s.count++
+
+	return nil
+}
+
+func (s *spillFrameStore) Range(from, to uint32, fn func(f *pb.FrameData) bool) error {
+	if to > s.count {
+		to = s.count
+	}
+
+	// 先读磁盘
+	if from < s.base {
+		end := s.base
+		if to < end {
+			end = to
+		}
+		stop := false
+		err := s.seg.rangeFrames(from, end, func(f *pb.FrameData) bool {
+			stop = !fn(f)
+			return !stop
+		})
+		if nil != err || stop {
+			return err
+		}
+		from = s.base
+	}
+
+	w := uint32(len(s.ring))
+	for ; from < to; from++ {
+		if f := s.ring[from%w]; nil != f {
+			if !fn(f) {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *spillFrameStore) Close() error {
+	s.ring = nil
+	return s.seg.remove()
+}
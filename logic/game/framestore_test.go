@@ -0,0 +1,62 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+func Test_SpillFrameStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frames.seg")
+	s, err := newSpillFrameStore(path, 16)
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	const total = 100
+	for i := uint32(0); i < total; i++ {
+		f := &pb.FrameData{FrameID: proto.Uint32(i)}
+		if 0 == i%3 {
+			f.Input = []*pb.InputData{{Id: proto.Uint64(uint64(i))}}
+		}
+		if err := s.Append(f); nil != err {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.Append(&pb.FrameData{FrameID: proto.Uint32(total + 1)}); nil == err {
+		t.Error("append discontinuous frame should fail")
+	}
+
+	if s.base != total-16 || len(s.seg.index) != 28 {
+		t.Errorf("base=%d spilled=%d", s.base, len(s.seg.index))
+	}
+
+	// 跨磁盘和内存读
+	var ids []uint32
+	if err := s.Range(60, 90, func(f *pb.FrameData) bool {
+		if f.GetInput()[0].GetId() != uint64(f.GetFrameID()) {
+			t.Errorf("frame[%d] input error", f.GetFrameID())
+		}
+		ids = append(ids, f.GetFrameID())
+		return true
+	}); nil != err {
+		t.Fatal(err)
+	}
+	if len(ids) != 10 || ids[0] != 60 || ids[9] != 87 {
+		t.Errorf("range [60,90) error %v", ids)
+	}
+
+	// 提前停止
+	n := 0
+	s.Range(0, total, func(f *pb.FrameData) bool {
+		n++
+		return n < 5
+	})
+	if n != 5 {
+		t.Errorf("range should stop at 5, got %d", n)
+	}
+}
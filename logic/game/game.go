@@ -1,6 +1,7 @@
 package game
 
 import (
+	"sort"
 	"time"
 
 	"github.com/byebyebruce/lockstepserver/pb"
@@ -19,14 +20,15 @@ const (
 	k_Gaming           = 1 // 战斗中阶段
 	k_Over             = 2 // 结束阶段
 	k_Stop             = 3 // 停止
+	k_Paused           = 4 // 暂停中
 )
 
 const (
-	MaxReadyTime          int64  = 20            // 准备阶段最长时间，如果超过这个时间没人连进来直接关闭游戏
 	MaxGameFrame          uint32 = 30*60*3 + 100 // 每局最大帧数
 	BroadcastOffsetFrames        = 3             // 每隔多少帧广播一次
 	kMaxFrameDataPerMsg          = 60            // 每个消息包最多包含多少个帧数据
-	kBadNetworkThreshold         = 2             // 这个时间段没有收到心跳包认为他网络很差，不再持续给发包(网络层的读写时间设置的比较长，客户端要求的方案)
+	kFrameMsgOverhead            = 16            // 帧消息里fromFrameID和toFrameID最多占多少字节
+	kFrameEntryOverhead          = 4             // 每个帧数据的tag和长度最多占多少字节
 )
 
 type gameListener interface {
@@ -34,40 +36,90 @@ type gameListener interface {
 	OnGameStart(uint64)
 	OnLeaveGame(uint64, uint64)
 	OnGameOver(uint64)
+	OnGamePause(uint64, bool)
+	OnNetworkChanged(uint64, uint64, bool)
 }
 
 // Game 一局游戏
 type Game struct {
 	id               uint64
 	startTime        int64
+	startTimeMs      int64
 	randomSeed       int32
+	cfg              *Config
 	State            GameState
 	players          map[uint64]*Player
+	spectators       map[uint64]*Player
+	nextSeat         int32
 	logic            *lockstep
 	clientFrameCount uint32
 
-	result map[uint64]uint64
+	result  map[uint64]*MatchResult
+	timeout bool
+	chatLog []ChatRecord
 
-	listener gameListener
+	snapshot       *snapshot // 最近一个达成一致的快照
+	snapCandidates map[snapshotKey]*snapshot
+
+	readyStart  time.Time // 进入准备阶段的时间
+	countdownAt time.Time // 倒计时结束的时间
+	hostStarted bool
+
+	turnStart time.Time // 回合制模式下当前帧开始的时间
+
+	restoreUntil time.Time // 从存档恢复之后等玩家重连的截止时间
+
+	now time.Time // 最近一次Tick的时间，处理消息和外部调用时也按这个算，不再读墙钟
+
+	pausedAt   time.Time
+	pausedBy   uint64
+	pausedTime time.Duration
+	pauseVotes map[uint64]time.Time
+
+	listener  gameListener
+	validator InputValidator
+	sim       *simRunner
 
 	dirty bool
 }
 
 // NewGame 构造游戏
-func NewGame(id uint64, players []uint64, randomSeed int32, listener gameListener) *Game {
+func NewGame(id uint64, players []uint64, randomSeed int32, cfg *Config, listener gameListener) *Game {
+	if nil == cfg {
+		cfg = DefaultConfig()
+	}
+	store, err := newFrameStore(id, cfg)
+	if nil != err {
+		l4g.Error("[game(%d)] create frame store error:[%s], use memory", id, err.Error())
+		store = newMemFrameStore()
+	}
+
+	now := time.Now()
 	g := &Game{
 		id:         id,
 		players:    make(map[uint64]*Player),
-		logic:      newLockstep(),
-		startTime:  time.Now().Unix(),
+		spectators: make(map[uint64]*Player),
+		logic:      newLockstep(store),
+		startTime:  now.Unix(),
+		readyStart: now,
+		now:        now,
 		randomSeed: randomSeed,
+		cfg:        cfg,
 		listener:   listener,
-		result:     make(map[uint64]uint64),
+		result:     make(map[uint64]*MatchResult),
+		pauseVotes: make(map[uint64]time.Time),
+
+		snapCandidates: make(map[snapshotKey]*snapshot),
+	}
+
+	if nil != cfg.NewInputValidator {
+		g.validator = cfg.NewInputValidator(id)
 	}
 
 	for k, v := range players {
 		g.players[v] = NewPlayer(v, int32(k+1))
 	}
+	g.nextSeat = int32(len(players))
 
 	return g
 }
@@ -85,13 +137,27 @@ func (g *Game) JoinGame(id uint64, conn *network.Conn) bool {
 		return false
 	}
 
-	if k_Ready != g.State && k_Gaming != g.State {
+	if k_Ready != g.State && !g.isPlaying() {
 		msg.ErrorCode = pb.ERRORCODE_ERR_RoomState.Enum()
 		p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), msg))
 		l4g.Error("[game(%d)] player[%d] game is over", g.id, id)
 		return true
 	}
 
+	// 主动离开的不能再回来，被踢的冷却时间过了才能回来
+	if p.left {
+		if !g.canRejoin(p) {
+			msg.ErrorCode = pb.ERRORCODE_ERR_Left.Enum()
+			if p.kicked {
+				msg.ErrorCode = pb.ERRORCODE_ERR_Kicked.Enum()
+			}
+			conn.AsyncWritePacket(pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), msg), 0)
+			l4g.Error("[game(%d)] player[%d] has left", g.id, id)
+			return false
+		}
+		g.rejoin(p)
+	}
+
 	// 把现有的玩家顶掉
 	if nil != p.client {
 		// TODO 这里有多线程操作的危险 如果调 p.client.Close() 会把现有刚进来的玩家提调
@@ -117,6 +183,12 @@ func (g *Game) LeaveGame(id uint64) bool {
 		return false
 	}
 
+	// 游戏中掉线通知其他人
+	if p.IsOnline() && g.isPlaying() {
+		g.pushEvent(p, pb.EVENT_EVT_Disconnect)
+		p.dropped = true
+	}
+
 	p.Cleanup()
 
 	g.listener.OnLeaveGame(g.id, id)
@@ -124,12 +196,17 @@ func (g *Game) LeaveGame(id uint64) bool {
 	return true
 }
 
-// ProcessMsg 处理消息
-func (g *Game) ProcessMsg(id uint64, msg *pb_packet.Packet) {
+// ProcessMsg 处理消息，conn是收到消息的连接
+func (g *Game) ProcessMsg(id uint64, conn *network.Conn, msg *pb_packet.Packet) {
 
 	player, ok := g.players[id]
 	if !ok {
-		l4g.Error("[game(%d)] processMsg player[%d] msg=[%d]", g.id, player.id, msg.GetMessageID())
+		l4g.Error("[game(%d)] processMsg player[%d] msg=[%d]", g.id, id, msg.GetMessageID())
+		return
+	}
+	// 已经离开(被踢)的玩家、被顶掉的旧连接还在路上的消息都丢掉
+	if player.left || player.client != conn {
+		l4g.Warn("[game(%d)] processMsg player[%d] msg=[%d] drop, left=[%v]", g.id, id, msg.GetMessageID(), player.left)
 		return
 	}
 	l4g.Info("[game(%d)] processMsg player[%d] msg=[%d]", g.id, player.id, msg.GetMessageID())
@@ -138,20 +215,7 @@ func (g *Game) ProcessMsg(id uint64, msg *pb_packet.Packet) {
 
 	switch msgID {
 	case pb.ID_MSG_JoinRoom:
-		msg := &pb.S2C_JoinRoomMsg{
-			Roomseatid: proto.Int32(player.idx),
-			RandomSeed: proto.Int32(g.randomSeed),
-		}
-
-		for _, v := range g.players {
-			if player.id == v.id {
-				continue
-			}
-			msg.Others = append(msg.Others, v.id)
-			msg.Pros = append(msg.Pros, v.loadingProgress)
-		}
-
-		player.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_JoinRoom), msg))
+		g.doJoinRoom(player)
 
 	case pb.ID_MSG_Progress:
 		if g.State > k_Ready {
@@ -173,14 +237,23 @@ func (g *Game) ProcessMsg(id uint64, msg *pb_packet.Packet) {
 	case pb.ID_MSG_Heartbeat:
 		player.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Heartbeat), nil))
 		player.RefreshHeartbeatTime()
+	case pb.ID_MSG_Ping:
+		g.doPing(player, msg)
+	case pb.ID_MSG_Start:
+		g.doHostStart(player)
 	case pb.ID_MSG_Ready:
 		if k_Ready == g.State {
 			g.doReady(player)
-		} else if k_Gaming == g.State {
+			g.broadcast(pb_packet.NewPacket(uint8(pb.ID_MSG_Countdown), g.countdownMessage()))
+		} else if g.isPlaying() {
 			g.doReady(player)
 			// 重连进来 TODO 对重连进行检查，重连比较耗费
 			g.doReconnect(player)
 			l4g.Warn("[game(%d)] doReconnect [%d]", g.id, player.id)
+			if player.dropped {
+				player.dropped = false
+				g.pushEvent(player, pb.EVENT_EVT_Reconnect)
+			}
 		} else {
 			l4g.Error("[game(%d)] ID_MSG_Ready player[%d] state error:[%d]", g.id, player.id, g.State)
 		}
@@ -191,21 +264,52 @@ func (g *Game) ProcessMsg(id uint64, msg *pb_packet.Packet) {
 			l4g.Error("[game(%d)] processMsg player[%d] msg=[%d] UnmarshalPB error:[%s]", g.id, player.id, msg.GetMessageID(), err.Error())
 			return
 		}
-		if !g.pushInput(player, m) {
+		if !g.pushInputs(player, m) {
 			l4g.Warn("[game(%d)] processMsg player[%d] msg=[%d] pushInput failed", g.id, player.id, msg.GetMessageID())
 			break
 		}
 
 		// 下一帧强制广播(客户端要求)
 		g.dirty = true
+	case pb.ID_MSG_Pause:
+		m := &pb.C2S_PauseMsg{}
+		if err := msg.Unmarshal(m); nil != err {
+			l4g.Error("[game(%d)] processMsg player[%d] msg=[%d] UnmarshalPB error:[%s]", g.id, player.id, msg.GetMessageID(), err.Error())
+			return
+		}
+		g.requestPause(player, m.GetPause())
+	case pb.ID_MSG_Surrender:
+		if !g.isPlaying() || player.surrendered {
+			break
+		}
+		player.surrendered = true
+		g.pushEvent(player, pb.EVENT_EVT_Surrender)
+		player.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Surrender), nil))
+	case pb.ID_MSG_Leave:
+		if !g.isPlaying() {
+			break
+		}
+		g.pushEvent(player, pb.EVENT_EVT_Leave)
+		player.left = true
+		player.Cleanup()
+	case pb.ID_MSG_Chat:
+		g.doChat(player, msg)
+	case pb.ID_MSG_Relay:
+		g.doRelay(player, msg)
+	case pb.ID_MSG_Snapshot:
+		g.doSnapshot(player, msg)
+	case pb.ID_MSG_Hash:
+		g.doHash(player, msg)
+	case pb.ID_MSG_Kick:
+		g.doHostKick(player, msg)
 	case pb.ID_MSG_Result:
 		m := &pb.C2S_ResultMsg{}
 		if err := msg.Unmarshal(m); nil != err {
 			l4g.Error("[game(%d)] processMsg player[%d] msg=[%d] UnmarshalPB error:[%s]", g.id, player.id, msg.GetMessageID(), err.Error())
 			return
 		}
-		g.result[player.id] = m.GetWinnerID()
-		l4g.Info("[game(%d)] ID_MSG_Result player[%d] winner=[%d]", g.id, player.id, m.GetWinnerID())
+		g.result[player.id] = newMatchResult(m)
+		l4g.Info("[game(%d)] ID_MSG_Result player[%d] winner=[%d] players=[%d]", g.id, player.id, m.GetWinnerID(), len(m.GetPlayers()))
 		player.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Result), nil))
 	default:
 		l4g.Warn("[game(%d)] processMsg unknown message id[%d]", msgID)
@@ -213,34 +317,16 @@ func (g *Game) ProcessMsg(id uint64, msg *pb_packet.Packet) {
 
 }
 
-// Tick 主逻辑
-func (g *Game) Tick(now int64) bool {
+// Tick 主逻辑，准备、暂停、回合、踢人冷却和存档恢复的超时都按now算
+func (g *Game) Tick(now time.Time) bool {
+	g.now = now
 
 	switch g.State {
 	case k_Ready:
-		delta := now - g.startTime
-		if delta < MaxReadyTime {
-			if g.checkReady() {
-				g.doStart()
-				g.State = k_Gaming
-			}
-
-		} else {
-			if g.getOnlinePlayerCount() > 0 {
-				// 大于最大准备时间，只要有在线的，就强制开始
-				g.doStart()
-				g.State = k_Gaming
-				l4g.Warn("[game(%d)] force start game because ready state is timeout ", g.id)
-			} else {
-				// 全都没连进来，直接结束
-				g.State = k_Over
-				l4g.Error("[game(%d)] game over!! nobody ready", g.id)
-			}
-		}
-
+		g.tickReady(now)
 		return true
 	case k_Gaming:
-		if g.checkOver() {
+		if g.checkOver(now) {
 			g.State = k_Over
 			l4g.Info("[game(%d)] game over successfully!!", g.id)
 			return true
@@ -248,13 +334,27 @@ func (g *Game) Tick(now int64) bool {
 
 		if g.isTimeout() {
 			g.State = k_Over
+			g.timeout = true
 			l4g.Warn("[game(%d)] game timeout", g.id)
 			return true
 		}
 
-		g.logic.tick()
-		g.broadcastFrameData()
+		if g.frameReady(now) {
+			g.logic.tick()
+			g.stepSimulation()
+		}
+		g.broadcastFrameData(now)
+		g.broadcastSpectatorFrames()
 
+		return true
+	case k_Paused:
+		// 暂停中帧不前进
+		if g.checkOver(now) {
+			g.State = k_Over
+			l4g.Info("[game(%d)] game over successfully while paused!!", g.id)
+			return true
+		}
+		g.tickPause(now)
 		return true
 	case k_Over:
 		g.doGameOver()
@@ -268,15 +368,51 @@ func (g *Game) Tick(now int64) bool {
 	return false
 }
 
-// Result 战斗结果
-func (g *Game) Result() map[uint64]uint64 {
+// PlayerStats 所有玩家的状态统计(按座位排序)
+func (g *Game) PlayerStats() []PlayerStats {
+	ret := make([]PlayerStats, 0, len(g.players))
+	for _, v := range g.players {
+		ret = append(ret, PlayerStats{
+			ID:     v.id,
+			Seat:   v.idx,
+			Team:   v.team,
+			Online: v.IsOnline(),
+			Ready:  v.isReady,
+			Net:    v.GetNetStats(),
+			Input:  v.GetInputStats(),
+
+			Degraded:  v.netDegraded,
+			SendQueue: v.GetSendQueueLen(),
+			AckLag:    v.GetAckLag(),
+			Desync:    v.desyncCount,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Seat < ret[j].Seat })
+	return ret
+}
+
+// FrameCount 当前帧数
+func (g *Game) FrameCount() uint32 {
+	return g.logic.getFrameCount()
+}
+
+// Result 战斗结果 playerID->结算
+func (g *Game) Result() map[uint64]*MatchResult {
 	return g.result
 }
 
-// Close 关闭游戏
-func (g *Game) Close() {
-	msg := pb_packet.NewPacket(uint8(pb.ID_MSG_Close), nil)
+// Close 关闭游戏，把关闭原因发给所有人
+func (g *Game) Close(reason pb.CLOSE) {
+	g.flushSpectators()
+
+	if pb.CLOSE_CLOSE_Over == reason && g.timeout {
+		reason = pb.CLOSE_CLOSE_Timeout
+	}
+	msg := pb_packet.NewPacket(uint8(pb.ID_MSG_Close), &pb.S2C_CloseMsg{
+		Reason: reason.Enum(),
+	})
 	g.broadcast(msg)
+	g.broadcastSpectators(msg)
 }
 
 // Cleanup 清理游戏
@@ -285,7 +421,60 @@ func (g *Game) Cleanup() {
 		v.Cleanup()
 	}
 	g.players = make(map[uint64]*Player)
+	for _, v := range g.spectators {
+		v.Cleanup()
+	}
+	g.spectators = make(map[uint64]*Player)
+	if nil != g.sim {
+		g.sim.close()
+	}
+	g.logic.close()
+}
+
+func (g *Game) doJoinRoom(p *Player) {
+	msg := &pb.S2C_JoinRoomMsg{
+		Roomseatid: proto.Int32(p.idx),
+		RandomSeed: proto.Int32(g.randomSeed),
+	}
+
+	for _, v := range g.players {
+		if p.id == v.id {
+			continue
+		}
+		msg.Others = append(msg.Others, v.id)
+		msg.Pros = append(msg.Pros, v.loadingProgress)
+	}
+
+	p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_JoinRoom), msg))
 
+	if k_Ready == g.State {
+		p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Countdown), g.countdownMessage()))
+	}
+}
+
+func (g *Game) doPing(p *Player, msg *pb_packet.Packet) {
+	recvTime := time.Now().UnixMilli()
+	m := &pb.C2S_PingMsg{}
+	if err := msg.Unmarshal(m); nil != err {
+		l4g.Error("[game(%d)] processMsg player[%d] msg=[%d] UnmarshalPB error:[%s]", g.id, p.id, msg.GetMessageID(), err.Error())
+		return
+	}
+	p.RefreshHeartbeatTime()
+
+	// 客户端带回了上次服务端发送的时间，减掉客户端停留的时间就是RTT
+	if echo := m.GetEchoTime(); echo > 0 {
+		p.netStats.update(time.Duration(recvTime-echo-m.GetHoldTime()) * time.Millisecond)
+	}
+	if nil != m.AckFrameID {
+		p.AckFrame(m.GetAckFrameID())
+	}
+
+	p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Ping), &pb.S2C_PingMsg{
+		ClientTime:     m.ClientTime,
+		ServerRecvTime: proto.Int64(recvTime),
+		ServerSendTime: proto.Int64(time.Now().UnixMilli()),
+		Rtt:            proto.Uint32(uint32(p.netStats.RTT / time.Millisecond)),
+	}))
 }
 
 func (g *Game) doReady(p *Player) {
@@ -302,6 +491,10 @@ func (g *Game) doReady(p *Player) {
 
 func (g *Game) checkReady() bool {
 	for _, v := range g.players {
+		// 被踢的不用等
+		if v.left {
+			continue
+		}
 		if !v.isReady {
 			return false
 		}
@@ -318,22 +511,95 @@ func (g *Game) doStart() {
 		v.isReady = true
 		v.loadingProgress = 100
 	}
-	g.startTime = time.Now().Unix()
-	msg := &pb.S2C_StartMsg{
-		TimeStamp: proto.Int64(g.startTime),
-	}
-	ret := pb_packet.NewPacket(uint8(pb.ID_MSG_Start), msg)
+	now := time.Now()
+	g.startTime = now.Unix()
+	g.startTimeMs = now.UnixMilli()
+	g.turnStart = now
+	ret := pb_packet.NewPacket(uint8(pb.ID_MSG_Start), g.startMessage())
 
 	g.broadcast(ret)
+	for _, v := range g.spectators {
+		if v.isReady {
+			v.SendMessage(ret)
+		}
+	}
+
+	g.newSimulation()
 
 	g.listener.OnGameStart(g.id)
 }
 
+func (g *Game) startMessage() *pb.S2C_StartMsg {
+	return &pb.S2C_StartMsg{
+		TimeStamp:     proto.Int64(g.startTime),
+		TimeStampMs:   proto.Int64(g.startTimeMs),
+		Mode:          g.cfg.LockstepMode.pb().Enum(),
+		TurnTimeoutMs: proto.Int64(g.cfg.TurnTimeout.Milliseconds()),
+		TickRate:      proto.Uint32(g.cfg.TickRate),
+	}
+}
+
+// Timeout 房间超时强制结束，还没结算的按超时出结果
+func (g *Game) Timeout() {
+	if k_Stop == g.State {
+		return
+	}
+	// 已经正常结束只是还没结算的不算超时
+	if k_Over != g.State {
+		g.timeout = true
+	}
+	g.doGameOver()
+	g.State = k_Stop
+	l4g.Warn("[game(%d)] room timeout at frame[%d]", g.id, g.logic.getFrameCount())
+}
+
 func (g *Game) doGameOver() {
 
 	g.listener.OnGameOver(g.id)
 }
 
+// pushInputs 先处理冗余的历史输入再处理本次输入，有一个成功就返回true
+func (g *Game) pushInputs(p *Player, msg *pb.C2S_InputMsg) bool {
+	ok := false
+	for _, v := range msg.History {
+		if g.acceptInput(p, v) {
+			ok = true
+		}
+	}
+	if g.acceptInput(p, msg) {
+		ok = true
+	}
+	return ok
+}
+
+// acceptInput 按序号去重之后放进帧里
+func (g *Game) acceptInput(p *Player, msg *pb.C2S_InputMsg) bool {
+	// 被踢掉的
+	if p.left {
+		return false
+	}
+
+	seq := msg.GetSeq()
+	dup, outOfOrder := p.inputSeq.check(seq)
+	if seq > 0 && dup {
+		p.inputStats.Duplicate++
+		return false
+	}
+
+	if !g.pushInput(p, msg) {
+		return false
+	}
+
+	if seq > 0 {
+		p.inputSeq.mark(seq)
+		if outOfOrder {
+			p.inputStats.OutOfOrder++
+		}
+	}
+	p.inputStats.Accepted++
+	return true
+}
+
 func (g *Game) pushInput(p *Player, msg *pb.C2S_InputMsg) bool {
 
 	cmd := &pb.InputData{
@@ -344,87 +610,80 @@ func (g *Game) pushInput(p *Player, msg *pb.C2S_InputMsg) bool {
 		Roomseatid: proto.Int32(p.idx),
 	}
 
-	return g.logic.pushCmd(cmd)
-}
-
-func (g *Game) doReconnect(p *Player) {
-
-	msg := &pb.S2C_StartMsg{
-		TimeStamp: proto.Int64(g.startTime),
+	idx, code := g.inputFrame(msg)
+	if pb.ERRORCODE_ERR_Ok != code {
+		// 迟到的输入以后也不会收下，记下序号，后面冗余的副本按重复丢掉，只通知一次
+		if pb.ERRORCODE_ERR_InputLate == code && msg.GetSeq() > 0 {
+			p.inputSeq.mark(msg.GetSeq())
+		}
+		if LateInputReport == g.cfg.LateInputPolicy {
+			p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_InputReject), &pb.S2C_InputRejectMsg{
+				FrameID:    proto.Uint32(msg.GetFrameID()),
+				CurFrameID: proto.Uint32(g.logic.getFrameCount()),
+				ErrorCode:  code.Enum(),
+			}))
+		}
+		return false
 	}
-	ret := pb_packet.NewPacket(uint8(pb.ID_MSG_Start), msg)
-	p.SendMessage(ret)
 
-	framesCount := g.clientFrameCount
-	var i uint32 = 0
-	c := 0
-	frameMsg := &pb.S2C_FrameMsg{}
-
-	for ; i < framesCount; i++ {
+	if !g.validateInput(p, idx, cmd) {
+		return false
+	}
 
-		frameData := g.logic.getFrame(i)
-		if nil == frameData && i != (framesCount-1) {
-			continue
-		}
+	return g.logic.pushCmd(idx, cmd)
+}
 
-		f := &pb.FrameData{
-			FrameID: proto.Uint32(i),
-		}
+// inputFrame 计算输入应该放到哪一帧
+func (g *Game) inputFrame(msg *pb.C2S_InputMsg) (uint32, pb.ERRORCODE) {
+	cur := g.logic.getFrameCount()
 
-		if nil != frameData {
-			f.Input = frameData.cmds
-		}
-		frameMsg.Frames = append(frameMsg.Frames, f)
-		c++
+	// 客户端没指定帧就认为是当前帧
+	idx := cur
+	if nil != msg.FrameID {
+		idx = msg.GetFrameID()
+	}
+	idx += g.cfg.InputDelay
 
-		if c >= kMaxFrameDataPerMsg || i == (framesCount-1) {
-			p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Frame), frameMsg))
-			c = 0
-			frameMsg = &pb.S2C_FrameMsg{}
+	if idx < cur {
+		if LateInputBump != g.cfg.LateInputPolicy {
+			return idx, pb.ERRORCODE_ERR_InputLate
 		}
+		idx = cur
 	}
 
-	p.SetSendFrameCount(g.clientFrameCount)
+	if idx > cur+g.cfg.MaxInputAhead {
+		return idx, pb.ERRORCODE_ERR_InputAhead
+	}
 
+	return idx, pb.ERRORCODE_ERR_Ok
 }
 
-func (g *Game) broadcastFrameData() {
-
-	framesCount := g.logic.getFrameCount()
-
-	if !g.dirty && framesCount-g.clientFrameCount < BroadcastOffsetFrames {
-		return
-	}
+func (g *Game) doReconnect(p *Player) {
 
-	defer func() {
-		g.dirty = false
-		g.clientFrameCount = framesCount
-	}()
+	p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Start), g.startMessage()))
 
-	/*
-		msg := &pb.S2C_FrameMsg{}
+	// 有快照就只发快照之后的帧
+	from := g.sendSnapshot(p, g.clientFrameCount)
+	g.sendFrames(p, from, g.clientFrameCount)
+	p.SetSendFrameCount(g.clientFrameCount)
 
-		for i := g.clientFrameCount; i < g.logic.getFrameCount(); i++ {
-			frameData := g.logic.getFrame(i)
+	if k_Paused == g.State {
+		p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Pause), g.pauseMessage(g.pausedBy)))
+	}
 
-			if nil == frameData && i != (g.logic.getFrameCount()-1) {
-				continue
-			}
+}
 
-			f := &pb.FrameData{}
-			f.FrameID = proto.Uint32(i)
-			msg.Frames = append(msg.Frames, f)
+func (g *Game) broadcastFrameData(now time.Time) {
 
-			if nil != frameData {
-				f.Input = frameData.cmds
-			}
+	framesCount := g.logic.getFrameCount()
 
-		}
-		if len(msg.Frames) > 0 {
-			g.broadcast(pb_packet.NewPacket(uint8(pb.ID_MSG_Frame), msg))
-		}
-	*/
-	now := time.Now().Unix()
+	broadcast := g.dirty || framesCount-g.clientFrameCount >= BroadcastOffsetFrames
+	if broadcast {
+		defer func() {
+			g.dirty = false
+			g.clientFrameCount = framesCount
+		}()
+	}
 
 	for _, p := range g.players {
 
@@ -437,44 +696,52 @@ func (g *Game) broadcastFrameData() {
 			continue
 		}
 
-		// 网络不好的
-		if now-p.GetLastHeartbeatTime() >= kBadNetworkThreshold {
-			continue
-		}
-
-		// 获得这个玩家已经发到哪一帧
-		i := p.GetSendFrameCount()
-		c := 0
-		msg := &pb.S2C_FrameMsg{}
-		for ; i < framesCount; i++ {
-			frameData := g.logic.getFrame(i)
-			if nil == frameData && i != (framesCount-1) {
+		// 网络不好的不断开，攒一批再发
+		g.checkNetwork(p, now)
+		if p.netDegraded {
+			if !g.shouldSendFrames(p, framesCount) {
 				continue
 			}
+		} else if !broadcast {
+			continue
+		}
 
-			f := &pb.FrameData{
-				FrameID: proto.Uint32(i),
-			}
+		// 从这个玩家已经发到的帧开始发
+		g.sendFrames(p, p.GetSendFrameCount(), framesCount)
+		p.SetSendFrameCount(framesCount)
 
-			if nil != frameData {
-				f.Input = frameData.cmds
-			}
-			msg.Frames = append(msg.Frames, f)
-			c++
-
-			// 如果是最后一帧或者达到这个消息包能装下的最大帧数，就发送
-			if i == (framesCount-1) || c >= kMaxFrameDataPerMsg {
-				p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Frame), msg))
-				c = 0
-				msg = &pb.S2C_FrameMsg{}
-			}
+	}
 
-		}
+}
 
-		p.SetSendFrameCount(framesCount)
+// sendFrames 把[from, to)的帧发给玩家，空帧不发，客户端靠消息里的帧范围区分空帧
+func (g *Game) sendFrames(p *Player, from, to uint32) {
+	if from >= to {
+		return
+	}
 
+	msg := &pb.S2C_FrameMsg{
+		FromFrameID: proto.Uint32(from),
 	}
+	size := kFrameMsgOverhead
+	for _, f := range g.logic.getRangeFrames(from, to) {
+		n := proto.Size(f) + kFrameEntryOverhead
+
+		// 达到这个消息包能装下的最大帧数或者最大长度，就发送
+		if len(msg.Frames) > 0 && (len(msg.Frames) >= kMaxFrameDataPerMsg || size+n > pb_packet.MaxPacketLen) {
+			msg.ToFrameID = f.FrameID
+			p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Frame), msg))
+			msg = &pb.S2C_FrameMsg{
+				FromFrameID: f.FrameID,
+			}
+			size = kFrameMsgOverhead
+		}
 
+		msg.Frames = append(msg.Frames, f)
+		size += n
+	}
+	msg.ToFrameID = proto.Uint32(to)
+	p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Frame), msg))
 }
 
 func (g *Game) broadcast(msg network.Packet) {
@@ -492,6 +759,11 @@ func (g *Game) broadcastExclude(msg network.Packet, id uint64) {
 	}
 }
 
+// isPlaying 游戏已经开始还没结束(包括暂停)
+func (g *Game) isPlaying() bool {
+	return k_Gaming == g.State || k_Paused == g.State
+}
+
 func (g *Game) getPlayer(id uint64) *Player {
 
 	return g.players[id]
@@ -514,10 +786,17 @@ func (g *Game) getOnlinePlayerCount() int {
 	return i
 }
 
-func (g *Game) checkOver() bool {
-	// 只要有人没发结果并且还在线，就不结束
+func (g *Game) checkOver(now time.Time) bool {
+	// 有权威模拟的以模拟为准
+	if g.simOver() {
+		return true
+	}
+
+	// 只要有人没发结果并且还在线，就不结束(投降和离开的不用等)
+	// 刚从存档恢复的时候掉线的玩家也要等
+	restoring := now.Before(g.restoreUntil)
 	for _, v := range g.players {
-		if !v.isOnline {
+		if (!v.isOnline && !restoring) || v.surrendered || v.left {
 			continue
 		}
 
@@ -530,7 +809,5 @@ func (g *Game) checkOver() bool {
 }
 
 func (g *Game) isTimeout() bool {
-	return g.logic.getFrameCount() > MaxGameFrame
-
-	return true
+	return g.logic.getFrameCount() > g.cfg.MaxFrames()
 }
@@ -0,0 +1,1500 @@
+package game
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/network"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+)
+
+type testListener struct{}
+
+func (t *testListener) OnJoinGame(uint64, uint64)             {}
+func (t *testListener) OnGameStart(uint64)                    {}
+func (t *testListener) OnLeaveGame(uint64, uint64)            {}
+func (t *testListener) OnGameOver(uint64)                     {}
+func (t *testListener) OnGamePause(uint64, bool)              {}
+func (t *testListener) OnNetworkChanged(uint64, uint64, bool) {}
+
+func newTestGame(cfg *Config, players ...uint64) *Game {
+	g := NewGame(1, players, 0, cfg, &testListener{})
+	g.doStart()
+	g.State = k_Gaming
+	return g
+}
+
+type testConnCallback struct{}
+
+func (testConnCallback) OnConnect(*network.Conn) bool                 { return true }
+func (testConnCallback) OnMessage(*network.Conn, network.Packet) bool { return true }
+func (testConnCallback) OnClose(*network.Conn)                        {}
+
+// newTestConn 用net.Pipe接一个真的连接，返回服务端的连接和客户端读消息的函数
+func newTestConn(t *testing.T) (*network.Conn, func() *pb_packet.Packet) {
+	server, client := net.Pipe()
+	cfg := &network.Config{
+		PacketSendChanLimit:    16,
+		PacketReceiveChanLimit: 16,
+		ConnReadTimeout:        time.Minute,
+		ConnWriteTimeout:       time.Minute,
+	}
+	conn := network.NewConn(server, network.NewServer(cfg, testConnCallback{}, &pb_packet.MsgProtocol{}))
+	conn.Do()
+	t.Cleanup(conn.Close)
+
+	read := func() *pb_packet.Packet {
+		client.SetReadDeadline(time.Now().Add(time.Second))
+		p, err := (&pb_packet.MsgProtocol{}).ReadPacket(client)
+		if nil != err {
+			t.Fatalf("read packet error: %s", err.Error())
+		}
+		return p.(*pb_packet.Packet)
+	}
+	return conn, read
+}
+
+func frameCmds(g *Game, idx uint32) int {
+	f := g.logic.getFrame(idx)
+	if nil == f {
+		return 0
+	}
+	return len(f.Input)
+}
+
+func Test_InputFrameID(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxInputAhead = 5
+	g := newTestGame(cfg, 1, 2)
+	g.logic.tick()
+	g.logic.tick()
+
+	// 没填帧ID放到当前帧
+	if !g.pushInput(g.getPlayer(1), &pb.C2S_InputMsg{Sid: proto.Int32(1)}) {
+		t.Fatal("push current frame failed")
+	}
+	if frameCmds(g, 2) != 1 {
+		t.Error("input should be in frame 2")
+	}
+
+	// 指定未来的帧
+	if !g.pushInput(g.getPlayer(2), &pb.C2S_InputMsg{FrameID: proto.Uint32(4)}) {
+		t.Fatal("push future frame failed")
+	}
+	if frameCmds(g, 4) != 1 {
+		t.Error("input should be in frame 4")
+	}
+
+	// 太超前
+	if g.pushInput(g.getPlayer(2), &pb.C2S_InputMsg{FrameID: proto.Uint32(8)}) {
+		t.Error("input too far ahead should be rejected")
+	}
+}
+
+func Test_LateInputPolicy(t *testing.T) {
+	cfg := DefaultConfig()
+	g := newTestGame(cfg, 1)
+	g.logic.tick()
+	g.logic.tick()
+	g.logic.tick()
+
+	late := &pb.C2S_InputMsg{FrameID: proto.Uint32(1)}
+
+	cfg.LateInputPolicy = LateInputReject
+	if g.pushInput(g.getPlayer(1), late) {
+		t.Error("late input should be rejected")
+	}
+
+	cfg.LateInputPolicy = LateInputBump
+	if !g.pushInput(g.getPlayer(1), late) {
+		t.Fatal("late input should be bumped")
+	}
+	if frameCmds(g, 1) != 0 || frameCmds(g, 3) != 1 {
+		t.Error("late input should be bumped to frame 3")
+	}
+}
+
+func Test_LateInputReport(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LateInputPolicy = LateInputReport
+	g := newTestGame(cfg, 1)
+	for i := 0; i < 5; i++ {
+		g.logic.tick()
+	}
+	conn, read := newTestConn(t)
+	p := g.getPlayer(1)
+	p.Connect(conn)
+
+	input := func(seq, frame uint32) *pb.C2S_InputMsg {
+		return &pb.C2S_InputMsg{Seq: proto.Uint32(seq), FrameID: proto.Uint32(frame)}
+	}
+	rejected := func(frame uint32) {
+		m := &pb.S2C_InputRejectMsg{}
+		pkt := read()
+		if pb.ID_MSG_InputReject != pb.ID(pkt.GetMessageID()) || nil != pkt.Unmarshal(m) {
+			t.Fatalf("should get input reject, got msg[%d]", pkt.GetMessageID())
+		}
+		if m.GetFrameID() != frame || m.GetErrorCode() != pb.ERRORCODE_ERR_InputLate {
+			t.Fatalf("reject error frame=%d code=%s", m.GetFrameID(), m.GetErrorCode())
+		}
+	}
+
+	// 迟到的输入冗余发了好几次，只通知一次
+	late := input(1, 1)
+	g.pushInputs(p, late)
+	g.pushInputs(p, &pb.C2S_InputMsg{Seq: proto.Uint32(2), FrameID: proto.Uint32(5), History: []*pb.C2S_InputMsg{late}})
+	g.pushInputs(p, &pb.C2S_InputMsg{Seq: proto.Uint32(3), FrameID: proto.Uint32(6), History: []*pb.C2S_InputMsg{late, input(2, 5)}})
+	g.pushInputs(p, input(4, 2))
+	rejected(1)
+	rejected(2)
+	if p.inputStats.Duplicate != 3 {
+		t.Errorf("redundant late copies should be duplicate, got %d", p.inputStats.Duplicate)
+	}
+}
+
+func Test_InputDelay(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.InputDelay = 2
+	g := newTestGame(cfg, 1, 2)
+
+	g.pushInput(g.getPlayer(1), &pb.C2S_InputMsg{})
+	g.pushInput(g.getPlayer(2), &pb.C2S_InputMsg{FrameID: proto.Uint32(0)})
+	if frameCmds(g, 2) != 2 {
+		t.Error("both inputs should be delayed to frame 2")
+	}
+
+	// 同一帧同一个玩家只能有一个操作
+	if g.pushInput(g.getPlayer(1), &pb.C2S_InputMsg{}) {
+		t.Error("second input in the same frame should be rejected")
+	}
+}
+
+func Test_InputRedundancy(t *testing.T) {
+	g := newTestGame(nil, 1)
+	p := g.getPlayer(1)
+
+	in := func(frameID, seq uint32) *pb.C2S_InputMsg {
+		return &pb.C2S_InputMsg{FrameID: proto.Uint32(frameID), Seq: proto.Uint32(seq)}
+	}
+
+	g.pushInputs(p, in(0, 1))
+	g.logic.tick()
+
+	// seq=2丢了，seq=3带着1和2
+	m := in(2, 3)
+	m.History = []*pb.C2S_InputMsg{in(0, 1), in(1, 2)}
+	g.pushInputs(p, m)
+	g.logic.tick()
+
+	// seq=2又迟到了
+	g.pushInputs(p, in(1, 2))
+
+	s := p.GetInputStats()
+	if s.Accepted != 3 || s.Duplicate != 2 || s.OutOfOrder != 0 {
+		t.Errorf("stats error %+v", s)
+	}
+
+	// seq=5先到，seq=4后到
+	g.pushInputs(p, in(4, 5))
+	g.pushInputs(p, in(3, 4))
+	s = p.GetInputStats()
+	if s.Accepted != 5 || s.OutOfOrder != 1 {
+		t.Errorf("stats error %+v", s)
+	}
+}
+
+func Test_InputRedundancyRetry(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxInputAhead = 2
+	g := newTestGame(cfg, 1)
+	p := g.getPlayer(1)
+
+	// 第一次发的时候太超前被拒绝
+	early := &pb.C2S_InputMsg{FrameID: proto.Uint32(3), Seq: proto.Uint32(1), Sid: proto.Int32(7)}
+	if g.acceptInput(p, early) {
+		t.Fatal("input too far ahead should be rejected")
+	}
+
+	// 冗余副本到的时候已经可以收下了
+	g.logic.tick()
+	if !g.acceptInput(p, early) {
+		t.Fatal("redundant copy should be accepted after first copy rejected")
+	}
+	if frameCmds(g, 3) != 1 {
+		t.Error("input should be in frame 3")
+	}
+
+	// 收下之后再来的才是重复
+	if g.acceptInput(p, early) {
+		t.Error("duplicate should be dropped")
+	}
+	if s := p.GetInputStats(); s.Accepted != 1 || s.Duplicate != 1 {
+		t.Errorf("stats error %+v", s)
+	}
+}
+
+func Test_RangeFrames(t *testing.T) {
+	g := newTestGame(nil, 1)
+	for i := uint32(0); i < 1000; i++ {
+		if 0 == i%100 {
+			g.pushInput(g.getPlayer(1), &pb.C2S_InputMsg{FrameID: proto.Uint32(i)})
+		}
+		g.logic.tick()
+	}
+	// 还没结束的帧不算
+	g.pushInput(g.getPlayer(1), &pb.C2S_InputMsg{FrameID: proto.Uint32(1000)})
+
+	if n := len(g.logic.getRangeFrames(0, 2000)); n != 10 {
+		t.Errorf("range [0,2000) should have 10 frames, got %d", n)
+	}
+	fs := g.logic.getRangeFrames(100, 300)
+	if len(fs) != 2 || fs[0].GetFrameID() != 100 || fs[1].GetFrameID() != 200 {
+		t.Errorf("range [100,300) error %v", fs)
+	}
+	if n := len(g.logic.getRangeFrames(101, 200)); n != 0 {
+		t.Errorf("range [101,200) should be empty, got %d", n)
+	}
+}
+
+func Test_NetStats(t *testing.T) {
+	s := NetStats{}
+	if s.update(-time.Millisecond) || s.update(time.Minute) {
+		t.Error("invalid sample should be ignored")
+	}
+
+	s.update(100 * time.Millisecond)
+	if s.RTT != 100*time.Millisecond || s.Jitter != 50*time.Millisecond {
+		t.Errorf("first sample error %+v", s)
+	}
+
+	for i := 0; i < 100; i++ {
+		s.update(20 * time.Millisecond)
+	}
+	if s.RTT > 21*time.Millisecond || s.Jitter > time.Millisecond {
+		t.Errorf("rtt should converge to 20ms %+v", s)
+	}
+}
+
+func Test_NetworkDegrade(t *testing.T) {
+	cfg := DefaultConfig()
+	g := newTestGame(cfg, 1)
+	p := g.getPlayer(1)
+	now := time.Now()
+	p.lastHeartbeatTime = now.UnixMilli()
+
+	p.netStats.update(time.Second)
+	g.checkNetwork(p, now)
+	if !p.netDegraded {
+		t.Fatal("high rtt should degrade")
+	}
+
+	// 网络差的攒够帧数再发
+	p.SetSendFrameCount(0)
+	if g.shouldSendFrames(p, cfg.DegradedSendFrames-1) || !g.shouldSendFrames(p, cfg.DegradedSendFrames) {
+		t.Error("degraded player should get frames in batches")
+	}
+
+	p.netStats = NetStats{}
+	p.netStats.update(20 * time.Millisecond)
+	g.checkNetwork(p, now)
+	g.checkNetwork(p, now.Add(cfg.NetRecoverTime/2))
+	if !p.netDegraded {
+		t.Fatal("should not recover before NetRecoverTime")
+	}
+	p.lastHeartbeatTime = now.Add(cfg.NetRecoverTime).UnixMilli()
+	g.checkNetwork(p, now.Add(cfg.NetRecoverTime))
+	if p.netDegraded {
+		t.Fatal("should recover after NetRecoverTime")
+	}
+
+	// 心跳超时
+	g.checkNetwork(p, now.Add(cfg.NetRecoverTime+cfg.SlowHeartbeat+time.Second))
+	if !p.netDegraded {
+		t.Fatal("heartbeat timeout should degrade")
+	}
+}
+
+func Test_Pause(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PauseBudget = 1
+	g := newTestGame(cfg, 1, 2)
+	p := g.getPlayer(1)
+	// 有人在线没交结果才不会结束
+	p.isOnline = true
+
+	g.Tick(time.Now())
+	g.requestPause(p, true)
+	if k_Paused != g.State {
+		t.Fatal("should be paused")
+	}
+	n := g.FrameCount()
+	g.Tick(time.Now())
+	if g.FrameCount() != n {
+		t.Error("frame should not advance while paused")
+	}
+
+	// 别人不能恢复
+	g.requestPause(g.getPlayer(2), false)
+	if k_Paused != g.State {
+		t.Fatal("only the player who paused can resume")
+	}
+	g.requestPause(p, false)
+	if k_Gaming != g.State {
+		t.Fatal("should be resumed")
+	}
+
+	// 次数用完
+	g.requestPause(p, true)
+	if k_Gaming != g.State {
+		t.Error("pause budget should be used up")
+	}
+
+	// 管理员不受限制，管理员的暂停玩家不能恢复
+	if !g.Pause(0) {
+		t.Fatal("admin pause failed")
+	}
+	g.requestPause(p, false)
+	if k_Paused != g.State {
+		t.Error("player should not resume admin pause")
+	}
+	if !g.Resume(0) {
+		t.Error("admin resume failed")
+	}
+}
+
+func Test_PauseTimeout(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxPauseTime = time.Minute
+	g := newTestGame(cfg, 1, 2)
+	g.getPlayer(1).isOnline = true
+
+	// 超时按Tick传进来的时间算
+	g.Pause(0)
+	now := time.Now()
+	g.Tick(now)
+	if k_Paused != g.State {
+		t.Fatal("should still be paused")
+	}
+	g.Tick(now.Add(cfg.MaxPauseTime))
+	if k_Gaming != g.State {
+		t.Error("pause should time out")
+	}
+}
+
+func Test_PauseVote(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PauseMode = PauseByVote
+	g := newTestGame(cfg, 1, 2, 3)
+	p1, p2, p3 := g.getPlayer(1), g.getPlayer(2), g.getPlayer(3)
+	p1.isOnline, p2.isOnline, p3.isOnline = true, true, true
+
+	// 3个人在线要2票
+	g.requestPause(p1, true)
+	if k_Gaming != g.State {
+		t.Fatal("one vote should not pause")
+	}
+	g.requestPause(p2, true)
+	if k_Paused != g.State || g.pausedBy != 2 {
+		t.Fatal("majority should pause")
+	}
+
+	// 恢复也要投票过半，投出暂停的最后一票的人也不能自己恢复
+	g.requestPause(p2, false)
+	if k_Paused != g.State {
+		t.Fatal("last pause voter should not resume alone")
+	}
+	g.requestPause(p1, false)
+	if k_Gaming != g.State {
+		t.Fatal("majority should resume")
+	}
+
+	// 没人投票恢复的等暂停超时
+	g.cfg.MaxPauseTime = time.Second
+	g.requestPause(p1, true)
+	g.requestPause(p3, true)
+	if k_Paused != g.State {
+		t.Fatal("majority should pause again")
+	}
+	g.tickPause(g.pausedAt.Add(time.Second))
+	if k_Gaming != g.State {
+		t.Fatal("pause should expire")
+	}
+}
+
+func Test_PauseTickTime(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PauseMode = PauseByVote
+	cfg.PauseVoteTimeout = time.Second
+	g := newTestGame(cfg, 1, 2)
+	p1, p2 := g.getPlayer(1), g.getPlayer(2)
+	p1.isOnline, p2.isOnline = true, true
+
+	// 暂停时间和票的有效期都按Tick的时间算
+	start := time.Now().Add(time.Hour)
+	g.Tick(start)
+	g.requestPause(p1, true)
+	g.Tick(start.Add(time.Second * 2))
+	g.requestPause(p2, true)
+	if k_Gaming != g.State {
+		t.Fatal("expired vote should not count")
+	}
+	g.requestPause(p1, true)
+	if k_Paused != g.State {
+		t.Fatal("majority should pause")
+	}
+
+	g.Tick(start.Add(time.Second * 7))
+	if g.PausedTime() != time.Second*5 {
+		t.Errorf("paused time should be 5s, got %v", g.PausedTime())
+	}
+	g.Resume(0)
+	if g.PausedTime() != time.Second*5 {
+		t.Errorf("paused time should be 5s after resume, got %v", g.PausedTime())
+	}
+}
+
+func Test_Spectator(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SpectatorDelayFrames = 10
+	g := NewGame(1, []uint64{1}, 0, cfg, &testListener{})
+	g.spectators[100] = NewPlayer(100, 0)
+	g.players[1].isReady = true
+
+	// 观战者不影响准备
+	if !g.checkReady() {
+		t.Error("spectator should not block ready")
+	}
+
+	g.doStart()
+	for i := 0; i < 15; i++ {
+		g.logic.tick()
+	}
+	if n := g.spectatorFrameCount(); n != 5 {
+		t.Errorf("spectator should see 5 frames, got %d", n)
+	}
+}
+
+func Test_SpectatorJoin(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxSpectators = 1
+	cfg.PauseMode = PauseByBudget
+	cfg.PauseBudget = 1
+	cfg.ChatRecord = true
+	cfg.HostKick = true
+	g := newTestGame(cfg, 1, 2)
+
+	conn, read := newTestConn(t)
+	if !g.JoinSpectator(100, conn) {
+		t.Fatal("spectator should join")
+	}
+	read()
+
+	// 超过MaxSpectators的拒绝
+	conn2, read2 := newTestConn(t)
+	if g.JoinSpectator(101, conn2) {
+		t.Fatal("spectators should be full")
+	}
+	m := &pb.S2C_ConnectMsg{}
+	if err := read2().Unmarshal(m); nil != err || m.GetErrorCode() != pb.ERRORCODE_ERR_SpectatorFull {
+		t.Fatalf("should get ERR_SpectatorFull %v %v", err, m.GetErrorCode())
+	}
+	if g.SpectatorCount() != 1 {
+		t.Fatalf("spectator count should be 1, got %d", g.SpectatorCount())
+	}
+
+	// 已经在的观战者重连不算满
+	conn3, read3 := newTestConn(t)
+	if !g.JoinSpectator(100, conn3) {
+		t.Fatal("spectator should rejoin")
+	}
+	read3()
+
+	// 观战者只能收，输入和其他会改游戏状态的消息都忽略
+	msgs := []*pb_packet.Packet{
+		pb_packet.NewPacket(uint8(pb.ID_MSG_Input), &pb.C2S_InputMsg{Sid: proto.Int32(1)}),
+		pb_packet.NewPacket(uint8(pb.ID_MSG_Pause), &pb.C2S_PauseMsg{Pause: proto.Bool(true)}),
+		pb_packet.NewPacket(uint8(pb.ID_MSG_Surrender), nil),
+		pb_packet.NewPacket(uint8(pb.ID_MSG_Leave), nil),
+		pb_packet.NewPacket(uint8(pb.ID_MSG_Chat), &pb.C2S_ChatMsg{Text: proto.String("hi")}),
+		pb_packet.NewPacket(uint8(pb.ID_MSG_Snapshot), &pb.C2S_SnapshotMsg{
+			FrameID: proto.Uint32(0), Hash: proto.Uint64(1), Total: proto.Uint32(1), Offset: proto.Uint32(0), Data: []byte{1},
+		}),
+		pb_packet.NewPacket(uint8(pb.ID_MSG_Kick), &pb.C2S_KickMsg{Id: proto.Uint64(2)}),
+		pb_packet.NewPacket(uint8(pb.ID_MSG_Result), &pb.C2S_ResultMsg{WinnerID: proto.Uint64(1)}),
+	}
+	for _, msg := range msgs {
+		g.ProcessSpectatorMsg(100, msg)
+	}
+
+	if n := frameCmds(g, g.logic.getFrameCount()); n != 0 {
+		t.Errorf("spectator input and events should be ignored, got %d", n)
+	}
+	if g.dirty {
+		t.Error("spectator input should not force broadcast")
+	}
+	if k_Gaming != g.State {
+		t.Error("spectator should not pause")
+	}
+	if len(g.chatLog) != 0 {
+		t.Error("spectator should not chat")
+	}
+	if len(g.snapCandidates) != 0 {
+		t.Error("spectator snapshot should be ignored")
+	}
+	if g.getPlayer(2).kicked {
+		t.Error("spectator should not kick")
+	}
+	if len(g.result) != 0 {
+		t.Error("spectator result should be ignored")
+	}
+	if _, ok := g.players[100]; ok {
+		t.Error("spectator should not become player")
+	}
+}
+
+func Test_AddRemovePlayer(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxPlayers = 3
+	g := newTestGame(cfg, 1, 2)
+	g.logic.tick()
+
+	seat, err := g.AddPlayer(3)
+	if nil != err || seat != 3 {
+		t.Fatalf("add player seat=%d err=%v", seat, err)
+	}
+	if _, err := g.AddPlayer(4); nil == err {
+		t.Error("players should be full")
+	}
+
+	f := g.logic.getFrame(1)
+	if nil == f || f.Input[0].GetEvent() != pb.EVENT_EVT_Join || f.Input[0].GetRoomseatid() != 3 {
+		t.Fatalf("join event error %v", f)
+	}
+
+	// 同一帧玩家的普通输入和系统事件不冲突
+	if !g.pushInput(g.getPlayer(3), &pb.C2S_InputMsg{}) {
+		t.Error("input should not conflict with event")
+	}
+
+	if err := g.RemovePlayer(2); nil != err {
+		t.Fatal(err)
+	}
+	seat, _ = g.AddPlayer(5)
+	if seat != 4 {
+		t.Errorf("seat should not be reused, got %d", seat)
+	}
+}
+
+func Test_SystemEvents(t *testing.T) {
+	g := newTestGame(nil, 1, 2)
+	p1, p2 := g.getPlayer(1), g.getPlayer(2)
+	p1.isOnline = true
+	p2.isOnline = true
+
+	surrender := pb_packet.NewPacket(uint8(pb.ID_MSG_Surrender), nil)
+	g.ProcessMsg(2, nil, surrender)
+	g.ProcessMsg(2, nil, surrender)
+	g.ProcessMsg(1, nil, pb_packet.NewPacket(uint8(pb.ID_MSG_Leave), nil))
+
+	f := g.logic.getFrame(0)
+	if nil == f || len(f.Input) != 2 ||
+		f.Input[0].GetEvent() != pb.EVENT_EVT_Surrender || f.Input[0].GetId() != 2 ||
+		f.Input[1].GetEvent() != pb.EVENT_EVT_Leave || f.Input[1].GetId() != 1 {
+		t.Fatalf("events error %v", f)
+	}
+	if !p1.left || !p2.surrendered {
+		t.Error("player flags error")
+	}
+
+	// 投降的不用等结果
+	if !g.checkOver(g.now) {
+		t.Error("game should be over")
+	}
+}
+
+func Test_Outcome(t *testing.T) {
+	cfg := DefaultConfig()
+	g := newTestGame(cfg, 1, 2, 3)
+
+	if o := g.Outcome(); o.Status != OutcomeNoResult {
+		t.Errorf("status should be no-result, got %s", o.Status)
+	}
+
+	g.result[1] = &MatchResult{WinnerID: 1}
+	g.result[2] = &MatchResult{WinnerID: 1}
+	g.result[3] = &MatchResult{WinnerID: 3}
+
+	cfg.ResultPolicy = ResultUnanimous
+	if o := g.Outcome(); o.Status != OutcomeDisputed || len(o.Dissenters) != 1 || o.Dissenters[0] != 3 {
+		t.Errorf("unanimous should be disputed %+v", o)
+	}
+
+	cfg.ResultPolicy = ResultMajority
+	if o := g.Outcome(); o.Status != OutcomeAgreed || o.WinnerID != 1 || o.Votes != 2 {
+		t.Errorf("majority should agree on 1 %+v", o)
+	}
+
+	cfg.ResultPolicy = ResultQuorum
+	cfg.ResultQuorum = 3
+	if o := g.Outcome(); o.Status != OutcomeDisputed {
+		t.Errorf("quorum 3 should be disputed %+v", o)
+	}
+
+	// 只有一部分玩家提交，分母是座位上的玩家数
+	delete(g.result, 2)
+	delete(g.result, 3)
+	cfg.ResultPolicy = ResultUnanimous
+	if o := g.Outcome(); o.Status != OutcomeNoQuorum || len(o.Dissenters) != 0 {
+		t.Errorf("unanimous with 1 of 3 should be no-quorum %+v", o)
+	}
+	cfg.ResultPolicy = ResultMajority
+	if o := g.Outcome(); o.Status != OutcomeNoQuorum {
+		t.Errorf("majority with 1 of 3 should be no-quorum %+v", o)
+	}
+	g.result[2] = &MatchResult{WinnerID: 1}
+	if o := g.Outcome(); o.Status != OutcomeAgreed || o.Votes != 2 {
+		t.Errorf("majority with 2 of 3 should agree %+v", o)
+	}
+	g.result[3] = &MatchResult{WinnerID: 1}
+
+	g.timeout = true
+	if o := g.Outcome(); o.Status != OutcomeTimeout {
+		t.Errorf("status should be timeout %+v", o)
+	}
+}
+
+func Test_MatchResult(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ResultPolicy = ResultUnanimous
+	g := newTestGame(cfg, 1, 2)
+
+	p1 := &pb.PlayerResult{Id: proto.Uint64(1), Rank: proto.Int32(1), Score: proto.Int64(100), Stats: map[string]int64{"kill": 3, "death": 1}}
+	p2 := &pb.PlayerResult{Id: proto.Uint64(2), Rank: proto.Int32(2), Score: proto.Int64(50), Stats: map[string]int64{"death": 3, "kill": 1}}
+
+	// 玩家顺序不同也算一致
+	g.result[1] = newMatchResult(&pb.C2S_ResultMsg{Players: []*pb.PlayerResult{p1, p2}, Blob: []byte("replay")})
+	g.result[2] = newMatchResult(&pb.C2S_ResultMsg{Players: []*pb.PlayerResult{p2, p1}, Blob: []byte("replay")})
+
+	o := g.Outcome()
+	if o.Status != OutcomeAgreed || nil == o.Result || len(o.Result.Players) != 2 || o.Result.Players[0].Stats["kill"] != 3 {
+		t.Fatalf("should agree %+v", o)
+	}
+
+	// 分数不一样就有分歧
+	p2.Score = proto.Int64(500)
+	g.result[2] = newMatchResult(&pb.C2S_ResultMsg{Players: []*pb.PlayerResult{p2, p1}, Blob: []byte("replay")})
+	if o := g.Outcome(); o.Status != OutcomeDisputed || len(o.Dissenters) != 1 {
+		t.Errorf("should be disputed %+v", o)
+	}
+}
+
+func Test_InputValidator(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxInputViolations = 3
+	cfg.NewInputValidator = func(id uint64) InputValidator {
+		return ChainValidator{
+			&RangeValidator{MinX: 0, MaxX: 100, MinY: 0, MaxY: 100},
+			&SkillValidator{Allowed: func(playerID uint64, seat int32, sid int32) bool { return sid < 10 }},
+			NewRateValidator(5),
+		}
+	}
+	g := newTestGame(cfg, 1, 2)
+	p := g.getPlayer(1)
+	p.isOnline = true
+
+	// 坐标越界被钳制
+	if !g.pushInput(p, &pb.C2S_InputMsg{Sid: proto.Int32(1), X: proto.Int32(200), Y: proto.Int32(-5)}) {
+		t.Fatal("clamped input should be accepted")
+	}
+	cmd := g.logic.getFrame(0).Input[0]
+	if cmd.GetX() != 100 || cmd.GetY() != 0 {
+		t.Errorf("input should be clamped, got %d,%d", cmd.GetX(), cmd.GetY())
+	}
+
+	// 禁止的技能
+	if g.pushInput(p, &pb.C2S_InputMsg{Sid: proto.Int32(10), FrameID: proto.Uint32(1)}) {
+		t.Error("forbidden skill should be rejected")
+	}
+
+	// 频率限制
+	p2 := g.getPlayer(2)
+	for i := 0; i < 6; i++ {
+		ok := g.pushInput(p2, &pb.C2S_InputMsg{Sid: proto.Int32(1), FrameID: proto.Uint32(uint32(i))})
+		if ok != (i < 5) {
+			t.Errorf("input[%d] rate limit error", i)
+		}
+	}
+
+	s := p.GetInputStats()
+	if s.Clamped != 1 || s.Rejected != 1 || p.left {
+		t.Errorf("stats error %+v", s)
+	}
+
+	// 超过违规次数踢掉
+	g.acceptInput(p, &pb.C2S_InputMsg{Sid: proto.Int32(11), FrameID: proto.Uint32(2)})
+	if !p.left {
+		t.Fatal("player should be kicked")
+	}
+	if g.acceptInput(p, &pb.C2S_InputMsg{Sid: proto.Int32(1), FrameID: proto.Uint32(3)}) {
+		t.Error("kicked player input should be ignored")
+	}
+
+	// 钳制的时候被踢，钳制后的输入不能跟在离开事件后面进帧
+	cfg.MaxInputViolations = 1
+	g = newTestGame(cfg, 1, 2)
+	p = g.getPlayer(1)
+	p.isOnline = true
+	if g.pushInput(p, &pb.C2S_InputMsg{Sid: proto.Int32(1), X: proto.Int32(200)}) || !p.left {
+		t.Fatal("clamped input of kicked player should be dropped")
+	}
+	if f := g.logic.getFrame(0); nil == f || len(f.Input) != 1 || f.Input[0].GetEvent() != pb.EVENT_EVT_Leave {
+		t.Errorf("frame should only have leave event %v", f)
+	}
+}
+
+// testSim 把所有输入的sid加起来，第over帧结束
+type testSim struct {
+	sum    uint64
+	frame  uint32
+	over   uint32
+	closed bool
+}
+
+func (s *testSim) Step(f *pb.FrameData) {
+	for _, v := range f.Input {
+		s.sum += uint64(v.GetSid())
+	}
+	s.frame = f.GetFrameID()
+}
+func (s *testSim) Hash() uint64 { return s.sum }
+func (s *testSim) Result() *MatchResult {
+	if s.frame < s.over {
+		return nil
+	}
+	return &MatchResult{WinnerID: s.sum}
+}
+func (s *testSim) Close() { s.closed = true }
+
+func Test_Simulation(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		sim := &testSim{over: 5}
+		cfg := DefaultConfig()
+		cfg.SimulationAsync = async
+		cfg.NewSimulation = func(id uint64, seed int32, players []uint64) (Simulation, error) {
+			if len(players) != 2 || players[0] != 1 {
+				t.Errorf("players error %v", players)
+			}
+			return sim, nil
+		}
+		g := newTestGame(cfg, 1, 2)
+		p1, p2 := g.getPlayer(1), g.getPlayer(2)
+		p1.isOnline, p2.isOnline = true, true
+
+		g.pushInput(p1, &pb.C2S_InputMsg{Sid: proto.Int32(3)})
+		g.Tick(time.Now())
+		for i := 0; i < 10 && g.State == k_Gaming; i++ {
+			g.Tick(time.Now())
+			if async {
+				time.Sleep(time.Millisecond * 10)
+			}
+		}
+		if g.State != k_Over {
+			t.Fatalf("async[%v] game should be over by simulation, state=%d", async, g.State)
+		}
+
+		// 客户端hash比较
+		hash := func(p *Player, frame uint32, h uint64) {
+			g.doHash(p, pb_packet.NewPacket(uint8(pb.ID_MSG_Hash), &pb.C2S_HashMsg{FrameID: proto.Uint32(frame), Hash: proto.Uint64(h)}))
+		}
+		hash(p1, 0, 3)
+		hash(p2, 0, 4)
+		if p1.desyncCount != 0 || p2.desyncCount != 1 {
+			t.Errorf("async[%v] desync count error %d %d", async, p1.desyncCount, p2.desyncCount)
+		}
+
+		// 以模拟结果为准
+		g.result[1] = &MatchResult{WinnerID: 1}
+		g.result[2] = &MatchResult{WinnerID: 1}
+		o := g.Outcome()
+		if !o.Simulated || o.WinnerID != 3 || len(o.Dissenters) != 2 {
+			t.Errorf("async[%v] outcome error %+v", async, o)
+		}
+
+		g.Cleanup()
+		if !sim.closed {
+			t.Errorf("async[%v] simulation should be closed", async)
+		}
+	}
+}
+
+// blockSim Step一直卡住，直到release关掉
+type blockSim struct {
+	testSim
+	release chan struct{}
+}
+
+func (s *blockSim) Step(f *pb.FrameData) {
+	<-s.release
+	s.testSim.Step(f)
+}
+
+func Test_SimulationLagging(t *testing.T) {
+	sim := &blockSim{release: make(chan struct{})}
+	s := newSimRunner(sim, true)
+
+	// 一帧卡在Step里，积压满了之后不会阻塞
+	n := 0
+	for i := 0; i < kSimQueueSize+2; i++ {
+		if s.push(&pb.FrameData{FrameID: proto.Uint32(uint32(i))}) {
+			n++
+		}
+	}
+	if !s.lagging || n > kSimQueueSize+1 {
+		t.Errorf("simulation should be lagging %v %d", s.lagging, n)
+	}
+
+	// out也满了，close不能卡住
+	close(sim.release)
+	for len(s.out) < kSimQueueSize {
+		time.Sleep(time.Millisecond)
+	}
+	done := make(chan struct{})
+	go func() {
+		s.close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("close blocked")
+	}
+	if !sim.closed {
+		t.Error("simulation should be closed")
+	}
+}
+
+func Test_TurnMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LockstepMode = LockstepTurn
+	cfg.TurnTimeout = time.Millisecond * 50
+	cfg.TurnTimeoutPolicy = TurnTimeoutEvent
+	g := newTestGame(cfg, 1, 2)
+	p1, p2 := g.getPlayer(1), g.getPlayer(2)
+	p1.isOnline, p2.isOnline = true, true
+
+	if g.startMessage().GetMode() != pb.MODE_MODE_Turn {
+		t.Error("start message mode error")
+	}
+
+	// 没人输入不前进
+	g.Tick(time.Now())
+	if g.FrameCount() != 0 {
+		t.Fatal("frame should wait for inputs")
+	}
+
+	// 一个人输入也不前进
+	g.pushInput(p1, &pb.C2S_InputMsg{Sid: proto.Int32(1)})
+	g.Tick(time.Now())
+	if g.FrameCount() != 0 {
+		t.Fatal("frame should wait for player 2")
+	}
+
+	// 都输入了就结束这一帧
+	g.pushInput(p2, &pb.C2S_InputMsg{Sid: proto.Int32(2)})
+	g.Tick(time.Now())
+	if g.FrameCount() != 1 {
+		t.Fatalf("frame should close, count=%d", g.FrameCount())
+	}
+
+	// 掉线的不用等
+	p2.isOnline = false
+	g.pushInput(p1, &pb.C2S_InputMsg{Sid: proto.Int32(1)})
+	g.Tick(time.Now())
+	if g.FrameCount() != 2 {
+		t.Fatalf("offline player should not block, count=%d", g.FrameCount())
+	}
+	p2.isOnline = true
+
+	// 超时插入事件
+	g.pushInput(p1, &pb.C2S_InputMsg{Sid: proto.Int32(1)})
+	time.Sleep(cfg.TurnTimeout)
+	g.Tick(time.Now())
+	if g.FrameCount() != 3 {
+		t.Fatalf("turn should timeout, count=%d", g.FrameCount())
+	}
+	f := g.logic.getFrame(2)
+	if nil == f || len(f.Input) != 2 || f.Input[1].GetEvent() != pb.EVENT_EVT_TurnTimeout || f.Input[1].GetId() != 2 {
+		t.Errorf("timeout event error %v", f)
+	}
+}
+
+func Test_Chat(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ChatRate = 2
+	cfg.ChatMaxLen = 8
+	cfg.ChatRecord = true
+	cfg.ChatFilter = func(id uint64, text string) (string, bool) {
+		return text, text != "bad"
+	}
+	g := newTestGame(cfg, 1, 2, 3)
+	g.SetTeam(1, 1)
+	g.SetTeam(2, 1)
+	g.SetTeam(3, 2)
+
+	chat := func(id uint64, scope pb.SCOPE, text string) {
+		g.ProcessMsg(id, nil, pb_packet.NewPacket(uint8(pb.ID_MSG_Chat), &pb.C2S_ChatMsg{Scope: scope.Enum(), Text: proto.String(text)}))
+	}
+
+	chat(1, pb.SCOPE_SCOPE_Team, "hi")
+	chat(2, pb.SCOPE_SCOPE_All, "too long text")
+	chat(2, pb.SCOPE_SCOPE_All, "bad")
+	chat(3, pb.SCOPE_SCOPE_All, "gg")
+	chat(3, pb.SCOPE_SCOPE_All, "gg")
+	chat(3, pb.SCOPE_SCOPE_All, "gg")
+
+	log := g.ChatLog()
+	if len(log) != 3 {
+		t.Fatalf("chat log should be 3, got %d", len(log))
+	}
+	if log[0].ID != 1 || log[0].Scope != pb.SCOPE_SCOPE_Team || log[0].TimeStampMs == 0 {
+		t.Errorf("chat record error %+v", log[0])
+	}
+	if log[2].ID != 3 {
+		t.Errorf("rate limited chat should not be recorded %+v", log[2])
+	}
+}
+
+func Test_Relay(t *testing.T) {
+	g := newTestGame(DefaultConfig(), 1, 2, 3)
+	g.SetTeam(1, 1)
+	g.SetTeam(2, 1)
+	p1, p2, p3 := g.getPlayer(1), g.getPlayer(2), g.getPlayer(3)
+
+	scope := func(s pb.SCOPE, seat int32) *pb.C2S_RelayMsg {
+		return &pb.C2S_RelayMsg{Scope: s.Enum(), Seat: proto.Int32(seat)}
+	}
+
+	cases := []struct {
+		msg  *pb.C2S_RelayMsg
+		want [3]bool
+	}{
+		{scope(pb.SCOPE_SCOPE_All, 0), [3]bool{true, true, true}},
+		{scope(pb.SCOPE_SCOPE_Others, 0), [3]bool{false, true, true}},
+		{scope(pb.SCOPE_SCOPE_Team, 0), [3]bool{true, true, false}},
+		{scope(pb.SCOPE_SCOPE_Seat, 3), [3]bool{false, false, true}},
+	}
+	for i, c := range cases {
+		for k, to := range []*Player{p1, p2, p3} {
+			if relayTo(p1, to, c.msg) != c.want[k] {
+				t.Errorf("case[%d] player[%d] error", i, to.id)
+			}
+		}
+	}
+
+	// 频率限制
+	now := time.Now()
+	for i := uint32(0); i < g.cfg.RelayRate; i++ {
+		if !p1.relayLimit.allow(now, g.cfg.RelayRate) {
+			t.Fatalf("relay[%d] should be allowed", i)
+		}
+	}
+	if p1.relayLimit.allow(now, g.cfg.RelayRate) {
+		t.Error("relay should be limited")
+	}
+	if !p1.relayLimit.allow(now.Add(time.Second), g.cfg.RelayRate) {
+		t.Error("relay should be allowed in next second")
+	}
+}
+
+func Test_RelayMsg(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RelayMaxSize = 8
+	g := newTestGame(cfg, 1, 2, 3)
+	g.SetTeam(1, 1)
+	g.SetTeam(2, 1)
+
+	reads := make(map[uint64]func() *pb_packet.Packet)
+	for _, id := range []uint64{1, 2, 3} {
+		conn, read := newTestConn(t)
+		g.getPlayer(id).Connect(conn)
+		reads[id] = read
+	}
+
+	relay := func(from uint64, m *pb.C2S_RelayMsg) {
+		g.ProcessMsg(from, g.getPlayer(from).client, pb_packet.NewPacket(uint8(pb.ID_MSG_Relay), m))
+	}
+	recv := func(id uint64) *pb.S2C_RelayMsg {
+		p := reads[id]()
+		m := &pb.S2C_RelayMsg{}
+		if pb.ID_MSG_Relay != pb.ID(p.GetMessageID()) || nil != p.Unmarshal(m) {
+			t.Fatalf("player[%d] should receive relay, got msg[%d]", id, p.GetMessageID())
+		}
+		return m
+	}
+
+	// 太长的不转发，只回给发送者错误码
+	relay(1, &pb.C2S_RelayMsg{Scope: pb.SCOPE_SCOPE_Others.Enum(), Type: proto.Int32(7), Data: []byte("too long data")})
+	if m := recv(1); m.GetErrorCode() != pb.ERRORCODE_ERR_TooLong || m.GetType() != 7 {
+		t.Errorf("sender should get too long %v", m)
+	}
+
+	// 正常的转发给队友，带上发送者的id和座位
+	relay(1, &pb.C2S_RelayMsg{Scope: pb.SCOPE_SCOPE_Team.Enum(), Type: proto.Int32(8), Data: []byte("hi")})
+	for _, id := range []uint64{1, 2} {
+		m := recv(id)
+		if m.GetErrorCode() != pb.ERRORCODE_ERR_Ok || m.GetId() != 1 || m.GetSeat() != g.getPlayer(1).idx ||
+			m.GetScope() != pb.SCOPE_SCOPE_Team || m.GetType() != 8 || string(m.GetData()) != "hi" {
+			t.Errorf("player[%d] relay error %v", id, m)
+		}
+	}
+
+	// 3不是队友，前面两条都没收到
+	relay(2, &pb.C2S_RelayMsg{Scope: pb.SCOPE_SCOPE_Seat.Enum(), Seat: proto.Int32(g.getPlayer(3).idx), Type: proto.Int32(9)})
+	if m := recv(3); m.GetId() != 2 || m.GetSeat() != g.getPlayer(2).idx || m.GetType() != 9 {
+		t.Errorf("player 3 should only receive seat relay %v", m)
+	}
+}
+
+func Test_Loading(t *testing.T) {
+	// 超时后准备好的人数够就开始，没准备好的删掉
+	cfg := DefaultConfig()
+	cfg.ReadyTimeout = time.Millisecond * 20
+	cfg.MinReadyPlayers = 2
+	cfg.LoadingPolicy = LoadingDrop
+	g := NewGame(1, []uint64{1, 2, 3}, 0, cfg, &testListener{})
+	g.getPlayer(1).isReady = true
+	g.Tick(time.Now())
+	if g.State != k_Ready {
+		t.Fatal("should wait for players")
+	}
+	g.getPlayer(2).isReady = true
+	time.Sleep(cfg.ReadyTimeout)
+	g.Tick(time.Now())
+	if g.State != k_Gaming || nil != g.getPlayer(3) {
+		t.Fatalf("should start and drop player 3, state=%d", g.State)
+	}
+	if f := g.logic.getFrame(0); nil == f || f.Input[0].GetEvent() != pb.EVENT_EVT_Remove {
+		t.Errorf("remove event error %v", f)
+	}
+
+	// 人数不够就结束
+	g = NewGame(1, []uint64{1, 2, 3}, 0, cfg, &testListener{})
+	g.getPlayer(1).isReady = true
+	time.Sleep(cfg.ReadyTimeout)
+	g.Tick(time.Now())
+	if g.State != k_Over {
+		t.Fatalf("should be over, state=%d", g.State)
+	}
+
+	// 房主开始加倒计时
+	cfg = DefaultConfig()
+	cfg.HostStart = true
+	cfg.StartCountdown = time.Millisecond * 20
+	g = NewGame(1, []uint64{1, 2}, 0, cfg, &testListener{})
+	g.getPlayer(2).isReady = true
+	g.doHostStart(g.getPlayer(2))
+	g.Tick(time.Now())
+	if g.State != k_Ready || !g.countdownAt.IsZero() {
+		t.Fatal("only host can start")
+	}
+	g.doHostStart(g.getPlayer(1))
+	g.Tick(time.Now())
+	if g.countdownAt.IsZero() || g.countdownMessage().GetStartTimeMs() == 0 {
+		t.Fatal("countdown should begin")
+	}
+	g.Tick(time.Now())
+	if g.State != k_Ready {
+		t.Fatal("should wait for countdown")
+	}
+	time.Sleep(cfg.StartCountdown)
+	g.Tick(time.Now())
+	if g.State != k_Gaming || nil == g.getPlayer(1) {
+		t.Fatalf("should start with late join, state=%d", g.State)
+	}
+}
+
+func Test_HostLeave(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.HostStart = true
+	cfg.HostKick = true
+	g := NewGame(1, []uint64{1, 2, 3}, 0, cfg, &testListener{})
+	for _, id := range []uint64{1, 2, 3} {
+		g.getPlayer(id).isOnline = true
+	}
+	if g.hostID() != 1 {
+		t.Fatalf("host should be 1, got %d", g.hostID())
+	}
+
+	// 房主掉线，下一个座位接替
+	g.getPlayer(1).isOnline = false
+	if g.hostID() != 2 {
+		t.Fatalf("host should pass to 2, got %d", g.hostID())
+	}
+	g.doHostStart(g.getPlayer(1))
+	if g.hostStarted {
+		t.Fatal("offline host can't start")
+	}
+
+	// 被踢的不能当房主，重新上线也不行
+	if err := g.Kick(2, 0, "", 0); nil != err {
+		t.Fatal(err)
+	}
+	g.getPlayer(1).isOnline = true
+	if g.hostID() != 1 {
+		t.Fatalf("host should back to 1, got %d", g.hostID())
+	}
+	g.getPlayer(1).left = true
+	if g.hostID() != 3 {
+		t.Fatalf("host should pass to 3, got %d", g.hostID())
+	}
+	g.doHostStart(g.getPlayer(3))
+	if !g.hostStarted {
+		t.Error("new host should start")
+	}
+
+	// 都不在线时还是座位号最小的
+	g.getPlayer(3).isOnline = false
+	if g.hostID() != 3 {
+		t.Errorf("host should be 3, got %d", g.hostID())
+	}
+}
+
+func Test_Snapshot(t *testing.T) {
+	g := newTestGame(DefaultConfig(), 1, 2, 3)
+	for i := 0; i < 10; i++ {
+		g.logic.tick()
+	}
+
+	upload := func(id uint64, frame uint32, hash uint64, data []byte, chunk int) {
+		for offset := 0; offset < len(data); offset += chunk {
+			end := offset + chunk
+			if end > len(data) {
+				end = len(data)
+			}
+			g.ProcessMsg(id, nil, pb_packet.NewPacket(uint8(pb.ID_MSG_Snapshot), &pb.C2S_SnapshotMsg{
+				FrameID: proto.Uint32(frame),
+				Hash:    proto.Uint64(hash),
+				Total:   proto.Uint32(uint32(len(data))),
+				Offset:  proto.Uint32(uint32(offset)),
+				Data:    data[offset:end],
+			}))
+		}
+	}
+
+	data := make([]byte, 2500)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	// 一个人上传不算
+	upload(1, 5, 0xabc, data, kSnapshotChunkSize)
+	if nil != g.snapshot {
+		t.Fatal("snapshot needs quorum")
+	}
+	if g.sendSnapshot(g.getPlayer(3), g.logic.getFrameCount()) != 0 {
+		t.Error("no snapshot should send from 0")
+	}
+
+	// hash不一样不算
+	upload(2, 5, 0xdef, data, kSnapshotChunkSize)
+	if nil != g.snapshot {
+		t.Fatal("snapshot hash mismatch")
+	}
+
+	// 乱序的分片丢掉
+	g.ProcessMsg(3, nil, pb_packet.NewPacket(uint8(pb.ID_MSG_Snapshot), &pb.C2S_SnapshotMsg{
+		FrameID: proto.Uint32(5), Hash: proto.Uint64(0xabc), Total: proto.Uint32(2500), Offset: proto.Uint32(700), Data: data[700:1400],
+	}))
+	if nil != g.snapshot {
+		t.Fatal("out of order chunk should be dropped")
+	}
+
+	upload(3, 5, 0xabc, data, 700)
+	if nil == g.snapshot || g.snapshot.frameID != 5 || len(g.snapshot.data) != len(data) {
+		t.Fatal("snapshot should be agreed")
+	}
+	if g.sendSnapshot(g.getPlayer(3), g.logic.getFrameCount()) != 6 {
+		t.Error("frames should start after snapshot")
+	}
+
+	// 还没到的帧不收
+	upload(1, 20, 0x1, data, kSnapshotChunkSize)
+	upload(2, 20, 0x1, data, kSnapshotChunkSize)
+	if g.snapshot.frameID != 5 {
+		t.Error("future snapshot should be ignored")
+	}
+}
+
+func Test_FrameBatch(t *testing.T) {
+	g := newTestGame(DefaultConfig(), 1, 2)
+	p1, p2 := g.getPlayer(1), g.getPlayer(2)
+
+	// 每帧一个很长的输入，60帧一个包会超过包长限制
+	const n = 200
+	for i := 0; i < n; i++ {
+		g.pushInput(p1, &pb.C2S_InputMsg{Sid: proto.Int32(-1), X: proto.Int32(-1), Y: proto.Int32(-1)})
+		g.logic.tick()
+	}
+
+	conn, read := newTestConn(t)
+	p2.Connect(conn)
+	g.sendFrames(p2, 0, n)
+
+	// 客户端用MsgProtocol读，每个包都不能超过包长限制，帧要连续
+	frames, from := 0, uint32(0)
+	for from < n {
+		pkt := read()
+		if len(pkt.GetData()) > pb_packet.MaxPacketLen {
+			t.Fatalf("frame packet too large %d", len(pkt.GetData()))
+		}
+		m := &pb.S2C_FrameMsg{}
+		if err := pkt.Unmarshal(m); nil != err {
+			t.Fatal(err)
+		}
+		if m.GetFromFrameID() != from || m.GetToFrameID() <= from {
+			t.Fatalf("frame range error [%d, %d) want from %d", m.GetFromFrameID(), m.GetToFrameID(), from)
+		}
+		frames += len(m.Frames)
+		from = m.GetToFrameID()
+	}
+	if frames != n {
+		t.Errorf("should receive %d frames, got %d", n, frames)
+	}
+}
+
+func Test_SnapshotQuorum(t *testing.T) {
+	for _, quorum := range []uint32{0, 1} {
+		cfg := DefaultConfig()
+		cfg.SnapshotQuorum = quorum
+		g := newTestGame(cfg, 1, 2)
+		for i := 0; i < 10; i++ {
+			g.logic.tick()
+		}
+
+		upload := func(id uint64) {
+			g.ProcessMsg(id, nil, pb_packet.NewPacket(uint8(pb.ID_MSG_Snapshot), &pb.C2S_SnapshotMsg{
+				FrameID: proto.Uint32(5),
+				Hash:    proto.Uint64(0xabc),
+				Total:   proto.Uint32(3),
+				Offset:  proto.Uint32(0),
+				Data:    []byte{1, 2, 3},
+			}))
+		}
+
+		// 配置小于2也要两个人一致
+		upload(1)
+		if nil != g.snapshot {
+			t.Fatalf("quorum %d: one player should not be enough", quorum)
+		}
+		upload(2)
+		if nil == g.snapshot {
+			t.Fatalf("quorum %d: two players should agree", quorum)
+		}
+	}
+}
+
+func Test_SnapshotChunk(t *testing.T) {
+	if kSnapshotChunkSize > 900 {
+		t.Fatalf("chunk size %d too large", kSnapshotChunkSize)
+	}
+
+	g := newTestGame(DefaultConfig(), 1)
+	data := make([]byte, kSnapshotChunkSize*2+10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	// 字段都取最大值，消息最长
+	g.snapshot = &snapshot{frameID: 0xffffffff - 1, hash: 0xffffffffffffffff, data: data}
+
+	conn, read := newTestConn(t)
+	p := g.getPlayer(1)
+	p.Connect(conn)
+	if g.sendSnapshot(p, 0xffffffff) != 0xffffffff {
+		t.Fatal("snapshot should be sent")
+	}
+
+	// 客户端用MsgProtocol读，每个分片都不能超过包长限制
+	var buf []byte
+	for len(buf) < len(data) {
+		pkt := read()
+		if len(pkt.GetData()) > pb_packet.MaxPacketLen {
+			t.Fatalf("chunk packet too large %d", len(pkt.GetData()))
+		}
+		m := &pb.S2C_SnapshotMsg{}
+		if err := pkt.Unmarshal(m); nil != err {
+			t.Fatal(err)
+		}
+		if m.GetHash() != 0xffffffffffffffff || m.GetTotal() != uint32(len(data)) || int(m.GetOffset()) != len(buf) {
+			t.Fatalf("chunk error frame[%d] offset[%d]", m.GetFrameID(), m.GetOffset())
+		}
+		buf = append(buf, m.GetData()...)
+	}
+	if string(buf) != string(data) {
+		t.Error("snapshot data mismatch")
+	}
+}
+
+func Test_Kick(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.HostKick = true
+	cfg.KickCooldown = time.Millisecond * 20
+	g := newTestGame(cfg, 1, 2, 3)
+	for _, id := range []uint64{1, 2, 3} {
+		g.getPlayer(id).isOnline = true
+	}
+
+	// 不是房主不能踢
+	kick := func(from, id uint64) {
+		g.ProcessMsg(from, nil, pb_packet.NewPacket(uint8(pb.ID_MSG_Kick), &pb.C2S_KickMsg{Id: proto.Uint64(id), Reason: proto.String("afk")}))
+	}
+	kick(2, 3)
+	if g.getPlayer(3).kicked {
+		t.Fatal("only host can kick")
+	}
+
+	kick(1, 3)
+	p := g.getPlayer(3)
+	if !p.kicked || !p.left || p.kickedUntil.IsZero() {
+		t.Fatal("player 3 should be kicked")
+	}
+	f := g.logic.getFrame(0)
+	if nil == f || f.Input[0].GetEvent() != pb.EVENT_EVT_Leave || f.Input[0].GetId() != 3 {
+		t.Errorf("leave event error %v", f)
+	}
+
+	// 被踢之后发的消息都丢掉
+	result := pb_packet.NewPacket(uint8(pb.ID_MSG_Result), &pb.C2S_ResultMsg{WinnerID: proto.Uint64(3)})
+	g.ProcessMsg(3, nil, result)
+	if nil != g.result[3] {
+		t.Error("kicked player msg should be dropped")
+	}
+
+	// 冷却时间内不能回来，连接要断开
+	connect := func(conn *network.Conn, read func() *pb_packet.Packet, ok bool, code pb.ERRORCODE) {
+		if g.JoinGame(3, conn) != ok {
+			t.Fatalf("join should return %v", ok)
+		}
+		m := &pb.S2C_ConnectMsg{}
+		if pkt := read(); pb.ID_MSG_Connect != pb.ID(pkt.GetMessageID()) || nil != pkt.Unmarshal(m) || m.GetErrorCode() != code {
+			t.Errorf("connect error code should be %s, got %s", code, m.GetErrorCode())
+		}
+	}
+	conn1, read1 := newTestConn(t)
+	connect(conn1, read1, false, pb.ERRORCODE_ERR_Kicked)
+	if nil != p.client || !p.left {
+		t.Error("kicked player should not join")
+	}
+
+	g.now = g.now.Add(cfg.KickCooldown)
+	if !g.canRejoin(p) || !p.left || !p.kicked {
+		t.Error("canRejoin should not change player")
+	}
+	conn2, read2 := newTestConn(t)
+	connect(conn2, read2, true, pb.ERRORCODE_ERR_Ok)
+	if p.left || p.kicked || !p.dropped || p.client != conn2 {
+		t.Error("kicked player should rejoin after cooldown")
+	}
+
+	// 旧连接的消息丢掉，新连接的正常处理
+	g.ProcessMsg(3, conn1, result)
+	if nil != g.result[3] {
+		t.Error("stale conn msg should be dropped")
+	}
+	g.ProcessMsg(3, conn2, result)
+	if nil == g.result[3] {
+		t.Error("rejoined player msg should be processed")
+	}
+
+	// 管理员永久踢
+	if err := g.Kick(2, 0, "grief", 0); nil != err {
+		t.Fatal(err)
+	}
+	g.now = g.now.Add(cfg.KickCooldown)
+	if g.canRejoin(g.getPlayer(2)) {
+		t.Error("banned player should not rejoin")
+	}
+	if err := g.Kick(4, 0, "", 0); nil == err {
+		t.Error("kick unknown player should fail")
+	}
+}
+
+func Test_Checkpoint(t *testing.T) {
+	newCfg := func(sim *testSim) *Config {
+		cfg := DefaultConfig()
+		cfg.NewSimulation = func(id uint64, seed int32, players []uint64) (Simulation, error) {
+			return sim, nil
+		}
+		return cfg
+	}
+
+	g := newTestGame(newCfg(&testSim{over: 100}), 1, 2)
+	p1, p2 := g.getPlayer(1), g.getPlayer(2)
+	p1.isOnline, p2.isOnline = true, true
+	g.SetTeam(2, 1)
+
+	g.pushInput(p1, &pb.C2S_InputMsg{Sid: proto.Int32(3)})
+	g.Tick(time.Now())
+	g.Tick(time.Now())
+	g.pushInput(p2, &pb.C2S_InputMsg{Sid: proto.Int32(4)})
+	g.Tick(time.Now())
+	g.result[1] = &MatchResult{WinnerID: 2}
+
+	// 只带from之后的帧
+	n := g.FrameCount()
+	if cp := g.Checkpoint(2); cp.FromFrame != 2 || len(cp.Frames) != 1 || cp.Frames[0].GetFrameID() != 2 {
+		t.Fatalf("checkpoint should only carry new frames %d %v", cp.FromFrame, cp.Frames)
+	}
+	if cp := g.Checkpoint(n + 1); cp.FromFrame != n || len(cp.Frames) != 0 {
+		t.Fatalf("checkpoint from should be clamped %d", cp.FromFrame)
+	}
+
+	// 模拟存盘再读出来，帧不在json里，由存储另外保存
+	saved := g.Checkpoint(0)
+	b, err := json.Marshal(saved)
+	if nil != err {
+		t.Fatal(err)
+	}
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(b, cp); nil != err {
+		t.Fatal(err)
+	}
+	if len(cp.Frames) != 0 {
+		t.Fatal("frames should not be in json")
+	}
+	cp.Frames = saved.Frames
+
+	sim := &testSim{over: 100}
+	r, err := RestoreGame(1, cp, newCfg(sim), &testListener{})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	if r.State != k_Gaming || r.FrameCount() != g.FrameCount() || frameCmds(r, 0) != 1 || frameCmds(r, 2) != 1 {
+		t.Fatalf("frames not restored state=%d frames=%d", r.State, r.FrameCount())
+	}
+	q1, q2 := r.getPlayer(1), r.getPlayer(2)
+	if nil == q1 || nil == q2 || q2.team != 1 || q1.IsOnline() || !q1.dropped {
+		t.Fatal("players not restored")
+	}
+	if r.result[1].WinnerID != 2 {
+		t.Error("result not restored")
+	}
+	if sim.sum != 7 || r.sim.lastFrame != g.FrameCount()-1 {
+		t.Errorf("simulation should replay all frames, sum=%d", sim.sum)
+	}
+
+	// 等玩家重连，不能因为都掉线了就结束
+	r.Tick(time.Now())
+	if r.State != k_Gaming {
+		t.Error("restored game should wait for players")
+	}
+	r.restoreUntil = time.Now()
+	r.Tick(time.Now())
+	if r.State != k_Over {
+		t.Error("game should be over after restore grace")
+	}
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// Kick 把玩家踢出房间，by为0表示管理员或者服务端，cooldown为0表示不能再进入
+func (g *Game) Kick(id, by uint64, reason string, cooldown time.Duration) error {
+	p, ok := g.players[id]
+	if !ok {
+		return fmt.Errorf("player[%d] not found", id)
+	}
+
+	if k_Ready != g.State && !g.isPlaying() {
+		return fmt.Errorf("game state[%d] error", g.State)
+	}
+
+	// 游戏中按离开处理，所有客户端在同一帧知道
+	if !p.left {
+		g.pushEvent(p, pb.EVENT_EVT_Leave)
+	}
+	p.left = true
+	p.kicked = true
+	p.kickedUntil = time.Time{}
+
+	msg := &pb.S2C_KickMsg{
+		Id:           proto.Uint64(id),
+		By:           proto.Uint64(by),
+		Reason:       proto.String(reason),
+		RejoinTimeMs: proto.Int64(0),
+	}
+	if cooldown > 0 {
+		p.kickedUntil = g.now.Add(cooldown)
+		msg.RejoinTimeMs = proto.Int64(p.kickedUntil.UnixMilli())
+	}
+	p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Kick), msg))
+	p.CleanupLater()
+
+	l4g.Warn("[game(%d)] player[%d] kicked by [%d] reason[%s] cooldown[%v]", g.id, id, by, reason, cooldown)
+
+	return nil
+}
+
+// doHostKick 房主踢人
+func (g *Game) doHostKick(p *Player, msg *pb_packet.Packet) {
+	m := &pb.C2S_KickMsg{}
+	if err := msg.Unmarshal(m); nil != err {
+		l4g.Error("[game(%d)] doHostKick player[%d] UnmarshalPB error:[%s]", g.id, p.id, err.Error())
+		return
+	}
+
+	if !g.cfg.HostKick || p.id != g.hostID() || p.id == m.GetId() {
+		l4g.Warn("[game(%d)] player[%d] can't kick [%d]", g.id, p.id, m.GetId())
+		return
+	}
+
+	if err := g.Kick(m.GetId(), p.id, m.GetReason(), g.cfg.KickCooldown); nil != err {
+		l4g.Warn("[game(%d)] host[%d] kick [%d] error:[%s]", g.id, p.id, m.GetId(), err.Error())
+	}
+}
+
+// canRejoin 被踢的玩家冷却时间过了可以重新进入
+func (g *Game) canRejoin(p *Player) bool {
+	return p.kicked && !p.kickedUntil.IsZero() && !g.now.Before(p.kickedUntil)
+}
+
+// rejoin 被踢的玩家重新进入，按重连处理
+func (g *Game) rejoin(p *Player) {
+	p.left = false
+	p.kicked = false
+	p.dropped = g.isPlaying()
+	l4g.Warn("[game(%d)] player[%d] rejoin after kicked", g.id, p.id)
+}
@@ -0,0 +1,153 @@
+package game
+
+import (
+	"sort"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// LoadingPolicy 开始时还没准备好的玩家怎么处理
+type LoadingPolicy int
+
+const (
+	LoadingLateJoin LoadingPolicy = 0 // 继续加载，准备好之后走重连流程进来
+	LoadingDrop     LoadingPolicy = 1 // 删掉座位
+)
+
+// tickReady 准备阶段：人齐了、房主开始或者超时的时候开始倒计时，倒计时结束开始游戏
+func (g *Game) tickReady(now time.Time) {
+	if !g.countdownAt.IsZero() {
+		if !now.Before(g.countdownAt) {
+			g.startGame()
+		}
+		return
+	}
+
+	ready := g.getReadyCount()
+	enough := ready > 0 && ready >= int(g.cfg.MinReadyPlayers)
+
+	if enough {
+		if g.cfg.HostStart {
+			if g.hostStarted {
+				g.beginCountdown(now)
+				return
+			}
+		} else if g.checkReady() {
+			g.beginCountdown(now)
+			return
+		}
+	}
+
+	if now.Sub(g.readyStart) < g.cfg.ReadyTimeout {
+		return
+	}
+
+	if enough {
+		// 超时了，准备好的人数够就强制开始
+		l4g.Warn("[game(%d)] force start game because ready state is timeout, ready[%d]", g.id, ready)
+		g.beginCountdown(now)
+	} else {
+		g.State = k_Over
+		l4g.Error("[game(%d)] game over!! not enough ready players[%d]", g.id, ready)
+	}
+}
+
+// beginCountdown 开始倒计时
+func (g *Game) beginCountdown(now time.Time) {
+	if g.cfg.StartCountdown <= 0 {
+		g.startGame()
+		return
+	}
+
+	g.countdownAt = now.Add(g.cfg.StartCountdown)
+	l4g.Info("[game(%d)] start countdown [%v]", g.id, g.cfg.StartCountdown)
+	g.broadcast(pb_packet.NewPacket(uint8(pb.ID_MSG_Countdown), g.countdownMessage()))
+}
+
+// startGame 开始游戏，按配置处理还在加载的玩家
+func (g *Game) startGame() {
+	var loading []*Player
+	for _, p := range g.players {
+		if !p.isReady {
+			loading = append(loading, p)
+		}
+	}
+	sort.Slice(loading, func(i, j int) bool { return loading[i].idx < loading[j].idx })
+
+	g.doStart()
+	g.State = k_Gaming
+
+	if LoadingDrop != g.cfg.LoadingPolicy {
+		return
+	}
+	for _, p := range loading {
+		l4g.Warn("[game(%d)] drop loading player[%d]", g.id, p.id)
+		g.RemovePlayer(p.id)
+	}
+}
+
+// doHostStart 房主开始
+func (g *Game) doHostStart(p *Player) {
+	if !g.cfg.HostStart || k_Ready != g.State || p.id != g.hostID() {
+		l4g.Warn("[game(%d)] player[%d] can't start game", g.id, p.id)
+		return
+	}
+
+	g.hostStarted = true
+}
+
+// hostID 座位号最小的玩家是房主，离开和被踢的不算，有人在线时优先在线的
+func (g *Game) hostID() uint64 {
+	var host, online *Player
+	for _, p := range g.players {
+		if p.left || p.kicked {
+			continue
+		}
+		if nil == host || p.idx < host.idx {
+			host = p
+		}
+		if p.isOnline && (nil == online || p.idx < online.idx) {
+			online = p
+		}
+	}
+	if nil != online {
+		return online.id
+	}
+	if nil == host {
+		return 0
+	}
+	return host.id
+}
+
+func (g *Game) getReadyCount() int {
+	n := 0
+	for _, p := range g.players {
+		if p.isReady {
+			n++
+		}
+	}
+	return n
+}
+
+func (g *Game) countdownMessage() *pb.S2C_CountdownMsg {
+	msg := &pb.S2C_CountdownMsg{
+		ReadyDeadlineMs: proto.Int64(g.readyStart.Add(g.cfg.ReadyTimeout).UnixMilli()),
+		StartTimeMs:     proto.Int64(0),
+		Ready:           proto.Uint32(uint32(g.getReadyCount())),
+		Total:           proto.Uint32(uint32(len(g.players))),
+		MinReady:        proto.Uint32(g.cfg.MinReadyPlayers),
+		HostID:          proto.Uint64(0),
+	}
+	if !g.countdownAt.IsZero() {
+		msg.StartTimeMs = proto.Int64(g.countdownAt.UnixMilli())
+	}
+	if g.cfg.HostStart {
+		msg.HostID = proto.Uint64(g.hostID())
+	}
+	return msg
+}
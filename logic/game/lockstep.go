@@ -2,6 +2,9 @@ package game
 
 import (
 	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
 )
 
 type frameData struct {
@@ -19,37 +22,51 @@ func newFrameData(index uint32) *frameData {
 }
 
 type lockstep struct {
-	frames     map[uint32]*frameData
+	frames     map[uint32]*frameData // 还没结束的帧
+	store      FrameStore            // 已经结束的帧
 	frameCount uint32
 }
 
-func newLockstep() *lockstep {
+func newLockstep(store FrameStore) *lockstep {
 	l := &lockstep{
 		frames: make(map[uint32]*frameData),
+		store:  store,
 	}
 
 	return l
 }
 
+// reset 只在开始前调用，这时候还没有结束的帧
 func (l *lockstep) reset() {
 	l.frames = make(map[uint32]*frameData)
 	l.frameCount = 0
 }
 
+func (l *lockstep) close() {
+	if err := l.store.Close(); nil != err {
+		l4g.Error("[lockstep] close frame store error:[%s]", err.Error())
+	}
+}
+
 func (l *lockstep) getFrameCount() uint32 {
 	return l.frameCount
 }
 
-func (l *lockstep) pushCmd(cmd *pb.InputData) bool {
-	f, ok := l.frames[l.frameCount]
+// pushCmd 把操作放到第idx帧，已经过去的帧不能再放
+func (l *lockstep) pushCmd(idx uint32, cmd *pb.InputData) bool {
+	if idx < l.frameCount {
+		return false
+	}
+
+	f, ok := l.frames[idx]
 	if !ok {
-		f = newFrameData(l.frameCount)
-		l.frames[l.frameCount] = f
+		f = newFrameData(idx)
+		l.frames[idx] = f
 	}
 
-	// 检查是否同一帧发来两次操作
+	// 检查是否同一帧发来两次操作(系统事件单独算)
 	for _, v := range f.cmds {
-		if v.Id == cmd.Id {
+		if v.GetId() == cmd.GetId() && v.GetEvent() == cmd.GetEvent() {
 			return false
 		}
 	}
@@ -59,26 +76,72 @@ func (l *lockstep) pushCmd(cmd *pb.InputData) bool {
 	return true
 }
 
+// hasInput 第idx帧是否已经有这个玩家的输入(系统事件不算)
+func (l *lockstep) hasInput(idx uint32, id uint64) bool {
+	f, ok := l.frames[idx]
+	if !ok {
+		return false
+	}
+
+	for _, v := range f.cmds {
+		if v.GetId() == id && pb.EVENT_EVT_None == v.GetEvent() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tick 结束当前帧，存起来
 func (l *lockstep) tick() uint32 {
+	f := &pb.FrameData{
+		FrameID: proto.Uint32(l.frameCount),
+	}
+	if v, ok := l.frames[l.frameCount]; ok {
+		f.Input = v.cmds
+		delete(l.frames, l.frameCount)
+	}
+	if err := l.store.Append(f); nil != err {
+		l4g.Error("[lockstep] store frame[%d] error:[%s]", l.frameCount, err.Error())
+	}
+
 	l.frameCount++
 	return l.frameCount
 }
 
-func (l *lockstep) getRangeFrames(from, to uint32) []*frameData {
-	ret := make([]*frameData, 0, to-from)
+// getRangeFrames 获得[from, to)之间已经结束的非空帧
+func (l *lockstep) getRangeFrames(from, to uint32) []*pb.FrameData {
+	if to > l.frameCount {
+		to = l.frameCount
+	}
 
-	for ; from <= to && from <= l.frameCount; from++ {
-		f, ok := l.frames[from]
-		if !ok {
-			continue
-		}
+	ret := make([]*pb.FrameData, 0)
+	if err := l.store.Range(from, to, func(f *pb.FrameData) bool {
 		ret = append(ret, f)
+		return true
+	}); nil != err {
+		l4g.Error("[lockstep] read frames [%d, %d) error:[%s]", from, to, err.Error())
 	}
 
 	return ret
 }
 
-func (l *lockstep) getFrame(idx uint32) *frameData {
+// getFrame 获得第idx帧，空帧返回nil
+func (l *lockstep) getFrame(idx uint32) *pb.FrameData {
+	if idx >= l.frameCount {
+		f, ok := l.frames[idx]
+		if !ok {
+			return nil
+		}
+		return &pb.FrameData{
+			FrameID: proto.Uint32(idx),
+			Input:   f.cmds,
+		}
+	}
 
-	return l.frames[idx]
+	fs := l.getRangeFrames(idx, idx+1)
+	if len(fs) == 0 {
+		return nil
+	}
+	return fs[0]
 }
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// isSlow 根据RTT、发送队列、确认延迟和心跳判断玩家现在网络是否很差
+func (g *Game) isSlow(p *Player, now time.Time) bool {
+	if g.cfg.SlowRTT > 0 && p.netStats.Samples > 0 && p.netStats.RTT > g.cfg.SlowRTT {
+		return true
+	}
+	if g.cfg.SlowSendQueue > 0 && p.GetSendQueueLen() > g.cfg.SlowSendQueue {
+		return true
+	}
+	if g.cfg.SlowAckLag > 0 && p.GetAckLag() > g.cfg.SlowAckLag {
+		return true
+	}
+	if g.cfg.SlowHeartbeat > 0 && time.Duration(now.UnixMilli()-p.GetLastHeartbeatTime())*time.Millisecond > g.cfg.SlowHeartbeat {
+		return true
+	}
+	return false
+}
+
+// checkNetwork 更新玩家的网络状态，指标正常持续NetRecoverTime才算恢复
+func (g *Game) checkNetwork(p *Player, now time.Time) {
+	if g.isSlow(p, now) {
+		p.netGoodSince = time.Time{}
+		if !p.netDegraded {
+			p.netDegraded = true
+			g.onNetworkChanged(p)
+		}
+		return
+	}
+
+	if !p.netDegraded {
+		return
+	}
+
+	if p.netGoodSince.IsZero() {
+		p.netGoodSince = now
+		return
+	}
+
+	if now.Sub(p.netGoodSince) >= g.cfg.NetRecoverTime {
+		p.netDegraded = false
+		p.netGoodSince = time.Time{}
+		g.onNetworkChanged(p)
+	}
+}
+
+// shouldSendFrames 网络差的玩家攒够DegradedSendFrames帧才发，发送队列积压太多就先不发
+func (g *Game) shouldSendFrames(p *Player, framesCount uint32) bool {
+	if !p.netDegraded {
+		return true
+	}
+
+	if g.cfg.SlowSendQueue > 0 && p.GetSendQueueLen() > g.cfg.SlowSendQueue {
+		return false
+	}
+
+	return framesCount-p.GetSendFrameCount() >= g.cfg.DegradedSendFrames
+}
+
+func (g *Game) onNetworkChanged(p *Player) {
+	if p.netDegraded {
+		l4g.Warn("[game(%d)] player[%d] network degraded rtt=[%v] queue=[%d] ackLag=[%d]", g.id, p.id, p.netStats.RTT, p.GetSendQueueLen(), p.GetAckLag())
+	} else {
+		l4g.Info("[game(%d)] player[%d] network recovered rtt=[%v]", g.id, p.id, p.netStats.RTT)
+	}
+
+	msg := pb_packet.NewPacket(uint8(pb.ID_MSG_NetState), &pb.S2C_NetStateMsg{
+		Id:       proto.Uint64(p.id),
+		Degraded: proto.Bool(p.netDegraded),
+		Rtt:      proto.Uint32(uint32(p.netStats.RTT / time.Millisecond)),
+	})
+	g.broadcast(msg)
+
+	g.listener.OnNetworkChanged(g.id, p.id, p.netDegraded)
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"time"
+)
+
+const kMaxRTTSample = 10 * time.Second // 超过这个值的采样认为是错误的
+
+// NetStats 玩家网络质量
+type NetStats struct {
+	RTT     time.Duration // 平滑后的RTT
+	Jitter  time.Duration // RTT的平均偏差
+	Samples uint32        // 采样次数
+}
+
+// update 按RFC6298的方式更新平滑RTT和偏差
+func (s *NetStats) update(sample time.Duration) bool {
+	if sample < 0 || sample > kMaxRTTSample {
+		return false
+	}
+
+	if 0 == s.Samples {
+		s.RTT = sample
+		s.Jitter = sample / 2
+	} else {
+		d := s.RTT - sample
+		if d < 0 {
+			d = -d
+		}
+		s.Jitter = (3*s.Jitter + d) / 4
+		s.RTT = (7*s.RTT + sample) / 8
+	}
+	s.Samples++
+
+	return true
+}
+
+// PlayerStats 玩家状态统计
+type PlayerStats struct {
+	ID     uint64
+	Seat   int32
+	Team   int32
+	Online bool
+	Ready  bool
+	Net    NetStats
+	Input  InputStats
+
+	Degraded  bool   // 网络是否变差
+	SendQueue int    // 发送队列积压
+	AckLag    uint32 // 已发送未确认的帧数
+	Desync    uint32 // 和服务端模拟不一致的次数
+}
@@ -0,0 +1,174 @@
+package game
+
+import (
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// PauseMode 玩家暂停的方式
+type PauseMode int
+
+const (
+	PauseDisabled PauseMode = 0 // 玩家不能暂停
+	PauseByBudget PauseMode = 1 // 每个玩家有固定的暂停次数
+	PauseByVote   PauseMode = 2 // 在线玩家过半投票才暂停
+)
+
+// Pause 暂停游戏，id为0表示管理员
+func (g *Game) Pause(id uint64) bool {
+	if k_Gaming != g.State {
+		return false
+	}
+
+	g.State = k_Paused
+	g.pausedAt = g.now
+	g.pausedBy = id
+	g.pauseVotes = make(map[uint64]time.Time)
+
+	l4g.Warn("[game(%d)] paused by [%d] at frame[%d]", g.id, id, g.logic.getFrameCount())
+	g.broadcast(pb_packet.NewPacket(uint8(pb.ID_MSG_Pause), g.pauseMessage(id)))
+	g.listener.OnGamePause(g.id, true)
+
+	return true
+}
+
+// Resume 恢复游戏，id为0表示管理员或者暂停超时
+func (g *Game) Resume(id uint64) bool {
+	if k_Paused != g.State {
+		return false
+	}
+
+	paused := g.now.Sub(g.pausedAt)
+	g.State = k_Gaming
+	g.pausedTime += paused
+	g.pauseVotes = make(map[uint64]time.Time)
+	// 暂停的时间不算回合超时
+	g.turnStart = g.turnStart.Add(paused)
+
+	l4g.Warn("[game(%d)] resumed by [%d] at frame[%d] paused=[%v]", g.id, id, g.logic.getFrameCount(), paused)
+	g.broadcast(pb_packet.NewPacket(uint8(pb.ID_MSG_Pause), g.pauseMessage(id)))
+	g.listener.OnGamePause(g.id, false)
+
+	return true
+}
+
+// PausedTime 累计暂停时间
+func (g *Game) PausedTime() time.Duration {
+	if k_Paused == g.State {
+		return g.pausedTime + g.now.Sub(g.pausedAt)
+	}
+	return g.pausedTime
+}
+
+// requestPause 处理玩家的暂停/恢复请求
+func (g *Game) requestPause(p *Player, pause bool) {
+	if !pause {
+		g.requestResume(p)
+		return
+	}
+
+	if k_Gaming != g.State {
+		return
+	}
+
+	switch g.cfg.PauseMode {
+	case PauseByBudget:
+		if p.pauseCount >= g.cfg.PauseBudget {
+			l4g.Warn("[game(%d)] player[%d] pause budget used up", g.id, p.id)
+			return
+		}
+		p.pauseCount++
+		g.Pause(p.id)
+
+	case PauseByVote:
+		g.pauseVotes[p.id] = g.now
+		votes, need := g.countPauseVotes()
+		if votes >= need {
+			g.Pause(p.id)
+			return
+		}
+		msg := g.pauseMessage(p.id)
+		msg.Votes = proto.Uint32(votes)
+		msg.NeedVotes = proto.Uint32(need)
+		g.broadcast(pb_packet.NewPacket(uint8(pb.ID_MSG_Pause), msg))
+
+	default:
+		l4g.Warn("[game(%d)] player[%d] pause is disabled", g.id, p.id)
+	}
+}
+
+// requestResume 暂停的玩家可以直接恢复，投票模式下的暂停要投票过半才能恢复(或者暂停超时)，管理员的暂停只有管理员能恢复
+func (g *Game) requestResume(p *Player) {
+	if k_Paused != g.State {
+		return
+	}
+
+	if 0 == g.pausedBy {
+		l4g.Warn("[game(%d)] player[%d] can not resume admin pause", g.id, p.id)
+		return
+	}
+
+	if PauseByVote != g.cfg.PauseMode {
+		if p.id == g.pausedBy {
+			g.Resume(p.id)
+			return
+		}
+		l4g.Warn("[game(%d)] player[%d] can not resume pause by [%d]", g.id, p.id, g.pausedBy)
+		return
+	}
+
+	// 暂停期间pauseVotes记录的是恢复的票
+	g.pauseVotes[p.id] = g.now
+	votes, need := g.countPauseVotes()
+	if votes >= need {
+		g.Resume(p.id)
+		return
+	}
+	msg := g.pauseMessage(p.id)
+	msg.Votes = proto.Uint32(votes)
+	msg.NeedVotes = proto.Uint32(need)
+	g.broadcast(pb_packet.NewPacket(uint8(pb.ID_MSG_Pause), msg))
+}
+
+// countPauseVotes 统计有效票数和需要的票数(在线玩家过半)
+func (g *Game) countPauseVotes() (uint32, uint32) {
+	votes := uint32(0)
+	for id, t := range g.pauseVotes {
+		if g.now.Sub(t) > g.cfg.PauseVoteTimeout {
+			delete(g.pauseVotes, id)
+			continue
+		}
+		if p, ok := g.players[id]; ok && p.isOnline {
+			votes++
+		}
+	}
+
+	online := uint32(0)
+	for _, v := range g.players {
+		if v.isOnline {
+			online++
+		}
+	}
+
+	return votes, online/2 + 1
+}
+
+// tickPause 暂停超时自动恢复
+func (g *Game) tickPause(now time.Time) {
+	if g.cfg.MaxPauseTime > 0 && now.Sub(g.pausedAt) >= g.cfg.MaxPauseTime {
+		g.Resume(0)
+	}
+}
+
+func (g *Game) pauseMessage(id uint64) *pb.S2C_PauseMsg {
+	return &pb.S2C_PauseMsg{
+		Paused:  proto.Bool(k_Paused == g.State),
+		Id:      proto.Uint64(id),
+		FrameID: proto.Uint32(g.logic.getFrameCount()),
+	}
+}
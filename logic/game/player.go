@@ -6,6 +6,18 @@ import (
 	"github.com/byebyebruce/lockstepserver/pkg/network"
 )
 
+// CloseDelay 被拒绝或者被踢的连接等最后的消息发出去再断开
+const CloseDelay = time.Millisecond * 200
+
+// InputStats 输入统计
+type InputStats struct {
+	Accepted   uint64 // 收下的输入
+	Duplicate  uint64 // 重复的输入(冗余发送)
+	OutOfOrder uint64 // 乱序到达的输入
+	Clamped    uint64 // 被校验修正的输入
+	Rejected   uint64 // 被校验丢弃的输入
+}
+
 type Player struct {
 	id                uint64
 	idx               int32
@@ -14,6 +26,24 @@ type Player struct {
 	loadingProgress   int32
 	lastHeartbeatTime int64
 	sendFrameCount    uint32
+	inputSeq          seqWindow
+	inputStats        InputStats
+	netStats          NetStats
+	ackFrameCount     uint32 // 客户端确认收到的帧数
+	hasAck            bool
+	netDegraded       bool
+	netGoodSince      time.Time
+	pauseCount        uint32 // 已经暂停的次数
+	desyncCount       uint32 // 和服务端模拟不一致的次数
+	team              int32  // 队伍
+	chatLimit         rateLimiter
+	relayLimit        rateLimiter
+	snapUpload        *snapshotUpload // 正在上传的快照
+	kicked            bool
+	kickedUntil       time.Time // 被踢之后可以重新进入的时间(零值表示不能再进入)
+	dropped           bool      // 游戏中掉线过，重连回来要通知
+	surrendered       bool      // 已经投降
+	left              bool      // 已经主动离开，不能再回来
 	client            *network.Conn
 }
 
@@ -30,7 +60,10 @@ func (p *Player) Connect(conn *network.Conn) {
 	p.client = conn
 	p.isOnline = true
 	p.isReady = false
-	p.lastHeartbeatTime = time.Now().Unix()
+	p.lastHeartbeatTime = time.Now().UnixMilli()
+	p.hasAck = false
+	// 重连后客户端的序号会重新开始
+	p.inputSeq.reset()
 }
 
 func (p *Player) IsOnline() bool {
@@ -38,13 +71,49 @@ func (p *Player) IsOnline() bool {
 }
 
 func (p *Player) RefreshHeartbeatTime() {
-	p.lastHeartbeatTime = time.Now().Unix()
+	p.lastHeartbeatTime = time.Now().UnixMilli()
 }
 
+// GetLastHeartbeatTime 最后一次心跳时间(毫秒)
 func (p *Player) GetLastHeartbeatTime() int64 {
 	return p.lastHeartbeatTime
 }
 
+// GetInputStats 获得输入统计
+func (p *Player) GetInputStats() InputStats {
+	return p.inputStats
+}
+
+// GetNetStats 获得网络质量
+func (p *Player) GetNetStats() NetStats {
+	return p.netStats
+}
+
+// AckFrame 客户端确认收到的帧数
+func (p *Player) AckFrame(c uint32) {
+	if c > p.sendFrameCount {
+		c = p.sendFrameCount
+	}
+	p.ackFrameCount = c
+	p.hasAck = true
+}
+
+// GetAckLag 已经发送但是客户端还没确认的帧数
+func (p *Player) GetAckLag() uint32 {
+	if !p.hasAck || p.ackFrameCount > p.sendFrameCount {
+		return 0
+	}
+	return p.sendFrameCount - p.ackFrameCount
+}
+
+// GetSendQueueLen 发送队列里积压的消息数
+func (p *Player) GetSendQueueLen() int {
+	if !p.IsOnline() {
+		return 0
+	}
+	return p.client.SendQueueLen()
+}
+
 func (p *Player) SetSendFrameCount(c uint32) {
 	p.sendFrameCount = c
 }
@@ -74,3 +143,19 @@ func (p *Player) Cleanup() {
 	p.isOnline = false
 
 }
+
+// CleanupLater 和Cleanup一样，但是等最后的消息发出去再断开，断开时不会再走LeaveGame
+func (p *Player) CleanupLater() {
+	if nil != p.client {
+		CloseLater(p.client)
+	}
+	p.client = nil
+	p.isReady = false
+	p.isOnline = false
+}
+
+// CloseLater 连接先和玩家解绑，等CloseDelay再断开，期间的消息都丢掉
+func CloseLater(conn *network.Conn) {
+	conn.PutExtraData(nil)
+	time.AfterFunc(CloseDelay, conn.Close)
+}
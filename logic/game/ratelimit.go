@@ -0,0 +1,22 @@
+package game
+
+import (
+	"time"
+)
+
+// rateLimiter 固定1秒窗口的频率限制
+type rateLimiter struct {
+	start time.Time
+	count uint32
+}
+
+// allow 这一秒内第几次，超过max返回false
+func (r *rateLimiter) allow(now time.Time, max uint32) bool {
+	if now.Sub(r.start) >= time.Second {
+		r.start = now
+		r.count = 0
+	}
+
+	r.count++
+	return r.count <= max
+}
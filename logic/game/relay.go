@@ -0,0 +1,65 @@
+package game
+
+import (
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// doRelay 客户端之间的自定义消息，服务端只加上发送者再按范围转发
+func (g *Game) doRelay(p *Player, msg *pb_packet.Packet) {
+	m := &pb.C2S_RelayMsg{}
+	if err := msg.Unmarshal(m); nil != err {
+		l4g.Error("[game(%d)] doRelay player[%d] UnmarshalPB error:[%s]", g.id, p.id, err.Error())
+		return
+	}
+
+	code := pb.ERRORCODE_ERR_Ok
+	if g.cfg.RelayRate > 0 && !p.relayLimit.allow(time.Now(), g.cfg.RelayRate) {
+		code = pb.ERRORCODE_ERR_RateLimit
+	} else if g.cfg.RelayMaxSize > 0 && len(m.GetData()) > g.cfg.RelayMaxSize {
+		code = pb.ERRORCODE_ERR_TooLong
+	}
+
+	if pb.ERRORCODE_ERR_Ok != code {
+		l4g.Warn("[game(%d)] player[%d] relay rejected[%s]", g.id, p.id, code)
+		p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Relay), &pb.S2C_RelayMsg{
+			ErrorCode: code.Enum(),
+			Type:      proto.Int32(m.GetType()),
+		}))
+		return
+	}
+
+	ret := pb_packet.NewPacket(uint8(pb.ID_MSG_Relay), &pb.S2C_RelayMsg{
+		ErrorCode: pb.ERRORCODE_ERR_Ok.Enum(),
+		Id:        proto.Uint64(p.id),
+		Seat:      proto.Int32(p.idx),
+		Scope:     m.GetScope().Enum(),
+		Type:      proto.Int32(m.GetType()),
+		Data:      m.GetData(),
+	})
+	for _, v := range g.players {
+		if !relayTo(p, v, m) {
+			continue
+		}
+		v.SendMessage(ret)
+	}
+}
+
+// relayTo to是否在接收范围内
+func relayTo(from, to *Player, m *pb.C2S_RelayMsg) bool {
+	switch m.GetScope() {
+	case pb.SCOPE_SCOPE_Team:
+		return from.team == to.team
+	case pb.SCOPE_SCOPE_Others:
+		return from.id != to.id
+	case pb.SCOPE_SCOPE_Seat:
+		return m.GetSeat() == to.idx
+	default:
+		return true
+	}
+}
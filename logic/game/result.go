@@ -0,0 +1,224 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+)
+
+// ResultPolicy 结果共识方式
+type ResultPolicy int
+
+const (
+	ResultUnanimous ResultPolicy = 0 // 所有玩家提交的结果一致
+	ResultMajority  ResultPolicy = 1 // 超过半数的玩家提交一致
+	ResultQuorum    ResultPolicy = 2 // 至少ResultQuorum个提交一致
+)
+
+var resultPolicyNames = map[ResultPolicy]string{
+	ResultUnanimous: "unanimous",
+	ResultMajority:  "majority",
+	ResultQuorum:    "quorum",
+}
+
+// MarshalText 输出成字符串
+func (p ResultPolicy) MarshalText() ([]byte, error) {
+	if s, ok := resultPolicyNames[p]; ok {
+		return []byte(s), nil
+	}
+	return nil, fmt.Errorf("unknown result policy[%d]", p)
+}
+
+// UnmarshalText 从配置文件里的字符串读取
+func (p *ResultPolicy) UnmarshalText(b []byte) error {
+	for k, v := range resultPolicyNames {
+		if v == string(b) {
+			*p = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown result policy[%s]", b)
+}
+
+// OutcomeStatus 最终结果状态
+type OutcomeStatus int
+
+const (
+	OutcomeAgreed   OutcomeStatus = 0 // 达成一致
+	OutcomeDisputed OutcomeStatus = 1 // 有分歧
+	OutcomeNoResult OutcomeStatus = 2 // 没人提交结果
+	OutcomeTimeout  OutcomeStatus = 3 // 超时结束
+	OutcomeFailed   OutcomeStatus = 4 // 服务端出错，结果无效
+	OutcomeNoQuorum OutcomeStatus = 5 // 提交的结果一致，但是提交的人数不够
+)
+
+func (s OutcomeStatus) String() string {
+	switch s {
+	case OutcomeAgreed:
+		return "agreed"
+	case OutcomeDisputed:
+		return "disputed"
+	case OutcomeNoResult:
+		return "no-result"
+	case OutcomeTimeout:
+		return "timeout"
+	case OutcomeFailed:
+		return "failed"
+	case OutcomeNoQuorum:
+		return "no-quorum"
+	}
+	return "unknown"
+}
+
+// MarshalText 输出成字符串
+func (s OutcomeStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// PlayerResult 单个玩家的结算
+type PlayerResult struct {
+	ID    uint64
+	Rank  int32
+	Team  int32
+	Score int64
+	Stats map[string]int64 `json:",omitempty"`
+}
+
+// MatchResult 一个玩家提交的整局结算
+type MatchResult struct {
+	WinnerID uint64
+	Players  []PlayerResult `json:",omitempty"`
+	Blob     []byte         `json:",omitempty"`
+}
+
+// newMatchResult 从消息构造，玩家按ID排序方便比较
+func newMatchResult(m *pb.C2S_ResultMsg) *MatchResult {
+	r := &MatchResult{
+		WinnerID: m.GetWinnerID(),
+		Blob:     m.GetBlob(),
+	}
+	for _, v := range m.GetPlayers() {
+		r.Players = append(r.Players, PlayerResult{
+			ID:    v.GetId(),
+			Rank:  v.GetRank(),
+			Team:  v.GetTeam(),
+			Score: v.GetScore(),
+			Stats: v.GetStats(),
+		})
+	}
+	sort.Slice(r.Players, func(i, j int) bool { return r.Players[i].ID < r.Players[j].ID })
+
+	return r
+}
+
+// key 结算的规范化表示，内容一样的结算key一样(json的map按key排序)
+func (r *MatchResult) key() string {
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+// Outcome 最终结果
+type Outcome struct {
+	Status      OutcomeStatus
+	WinnerID    uint64                  // 达成一致的胜利者
+	Result      *MatchResult            // 达成一致的结算
+	Votes       int                     // 支持这个结果的人数
+	Dissenters  []uint64                // 和多数结果不一致的玩家
+	Simulated   bool                    // 结算来自服务端权威模拟
+	Submissions map[uint64]*MatchResult // 原始提交 playerID->结算
+	Error       string                  `json:",omitempty"` // OutcomeFailed时的出错原因
+}
+
+// Outcome 根据配置的共识方式计算最终结果
+func (g *Game) Outcome() *Outcome {
+	o := &Outcome{
+		Status:      OutcomeNoResult,
+		Submissions: make(map[uint64]*MatchResult, len(g.result)),
+	}
+	for k, v := range g.result {
+		o.Submissions[k] = v
+	}
+
+	if nil != g.sim && nil != g.sim.result {
+		// 有权威模拟结果就不看客户端提交
+		o.Status = OutcomeAgreed
+		o.Simulated = true
+		o.Result = g.sim.result
+		o.WinnerID = g.sim.result.WinnerID
+
+		key := g.sim.result.key()
+		for k, v := range g.result {
+			if v.key() == key {
+				o.Votes++
+			} else {
+				o.Dissenters = append(o.Dissenters, k)
+			}
+		}
+		sort.Slice(o.Dissenters, func(i, j int) bool { return o.Dissenters[i] < o.Dissenters[j] })
+	} else if len(g.result) > 0 {
+		winner, votes := pluralityResult(g.result)
+
+		key := winner.key()
+		for k, v := range g.result {
+			if v.key() != key {
+				o.Dissenters = append(o.Dissenters, k)
+			}
+		}
+		sort.Slice(o.Dissenters, func(i, j int) bool { return o.Dissenters[i] < o.Dissenters[j] })
+
+		// 按座位上的玩家数算，不然只有一个人提交也能"全体一致"
+		if g.isAgreed(votes, len(g.players)) {
+			o.Status = OutcomeAgreed
+			o.WinnerID = winner.WinnerID
+			o.Result = winner
+			o.Votes = votes
+		} else if len(o.Dissenters) > 0 {
+			o.Status = OutcomeDisputed
+		} else {
+			o.Status = OutcomeNoQuorum
+		}
+	}
+
+	if g.timeout {
+		o.Status = OutcomeTimeout
+	}
+
+	return o
+}
+
+// isAgreed votes个一致的提交够不够，total是有资格提交的玩家数(不算观战)
+func (g *Game) isAgreed(votes, total int) bool {
+	switch g.cfg.ResultPolicy {
+	case ResultMajority:
+		return votes*2 > total
+	case ResultQuorum:
+		return votes >= int(g.cfg.ResultQuorum)
+	default:
+		return votes == total
+	}
+}
+
+// pluralityResult 获得票数最多的结算，票数一样取key小的
+func pluralityResult(result map[uint64]*MatchResult) (*MatchResult, int) {
+	count := make(map[string]int)
+	first := make(map[string]*MatchResult)
+	for _, v := range result {
+		k := v.key()
+		count[k]++
+		first[k] = v
+	}
+
+	var (
+		winner string
+		votes  int
+	)
+	for k, v := range count {
+		if v > votes || (v == votes && k < winner) {
+			winner, votes = k, v
+		}
+	}
+
+	return first[winner], votes
+}
@@ -0,0 +1,152 @@
+package game
+
+import (
+	"encoding/binary"
+	"os"
+	"sort"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+const kSegmentHeaderLen = 8 // 帧ID(uint32) + 数据长度(uint32)
+
+type segmentEntry struct {
+	idx uint32
+	off int64
+	len uint32
+}
+
+/*
+segment 只追加的帧数据文件
+
+|--frameID(uint32)--|--dataLen(uint32)--|--------FrameData--------|
+|---------4---------|---------4---------|---------dataLen---------|
+*/
+type segment struct {
+	file  *os.File
+	size  int64
+	index []segmentEntry // 按帧ID有序
+}
+
+func openSegment(path string) (*segment, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if nil != err {
+		return nil, err
+	}
+	return &segment{
+		file: f,
+	}, nil
+}
+
+// loadSegment 打开已有的文件重建索引，没有就创建，最后写了一半的记录截掉
+func loadSegment(path string) (*segment, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if nil != err {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if nil != err {
+		f.Close()
+		return nil, err
+	}
+
+	s := &segment{
+		file: f,
+	}
+	head := make([]byte, kSegmentHeaderLen)
+	for s.size+kSegmentHeaderLen <= info.Size() {
+		if _, err := f.ReadAt(head, s.size); nil != err {
+			f.Close()
+			return nil, err
+		}
+		e := segmentEntry{
+			idx: binary.BigEndian.Uint32(head),
+			off: s.size + kSegmentHeaderLen,
+			len: binary.BigEndian.Uint32(head[4:]),
+		}
+		if e.off+int64(e.len) > info.Size() {
+			break
+		}
+		s.index = append(s.index, e)
+		s.size = e.off + int64(e.len)
+	}
+
+	if s.size < info.Size() {
+		if err := f.Truncate(s.size); nil != err {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *segment) append(f *pb.FrameData) error {
+	data, err := proto.Marshal(f)
+	if nil != err {
+		return err
+	}
+
+	buff := make([]byte, kSegmentHeaderLen, kSegmentHeaderLen+len(data))
+	binary.BigEndian.PutUint32(buff, f.GetFrameID())
+	binary.BigEndian.PutUint32(buff[4:], uint32(len(data)))
+	buff = append(buff, data...)
+
+	if _, err := s.file.WriteAt(buff, s.size); nil != err {
+		return err
+	}
+
+	s.index = append(s.index, segmentEntry{
+		idx: f.GetFrameID(),
+		off: s.size + kSegmentHeaderLen,
+		len: uint32(len(data)),
+	})
+	s.size += int64(len(buff))
+
+	return nil
+}
+
+// rangeFrames 按顺序读取[from, to)之间的帧
+func (s *segment) rangeFrames(from, to uint32, fn func(f *pb.FrameData) bool) error {
+	i := sort.Search(len(s.index), func(i int) bool { return s.index[i].idx >= from })
+	for ; i < len(s.index) && s.index[i].idx < to; i++ {
+		e := s.index[i]
+		data := make([]byte, e.len)
+		if _, err := s.file.ReadAt(data, e.off); nil != err {
+			return err
+		}
+
+		f := &pb.FrameData{}
+		if err := proto.Unmarshal(data, f); nil != err {
+			return err
+		}
+		if !fn(f) {
+			break
+		}
+	}
+	return nil
+}
+
+// truncate 删掉帧ID>=from的帧
+func (s *segment) truncate(from uint32) error {
+	i := sort.Search(len(s.index), func(i int) bool { return s.index[i].idx >= from })
+	if i == len(s.index) {
+		return nil
+	}
+
+	size := s.index[i].off - kSegmentHeaderLen
+	if err := s.file.Truncate(size); nil != err {
+		return err
+	}
+	s.size = size
+	s.index = s.index[:i]
+
+	return nil
+}
+
+// remove 关闭并删除文件
+func (s *segment) remove() error {
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
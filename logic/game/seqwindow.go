@@ -0,0 +1,46 @@
+package game
+
+const kSeqWindowSize = 64 // 去重窗口大小
+
+// seqWindow 输入序号滑动窗口，用来过滤冗余发送的重复输入
+type seqWindow struct {
+	max  uint32 // 收到的最大序号
+	mask uint64 // 第i位表示序号max-i是否收到过
+}
+
+func (w *seqWindow) reset() {
+	w.max = 0
+	w.mask = 0
+}
+
+// check 返回是否重复和是否乱序到达(比已收到的最大序号小)，不记录
+// 比窗口还旧的序号当作重复处理
+func (w *seqWindow) check(seq uint32) (dup bool, outOfOrder bool) {
+	if seq > w.max {
+		return false, false
+	}
+
+	d := w.max - seq
+	if d >= kSeqWindowSize || 0 != w.mask&(1<<d) {
+		return true, false
+	}
+	return false, true
+}
+
+// mark 记录序号，输入真正收下或者已经迟到之后才调用，其他原因被拒绝的输入后面的冗余副本还有机会
+func (w *seqWindow) mark(seq uint32) {
+	if seq > w.max {
+		shift := seq - w.max
+		if shift >= kSeqWindowSize {
+			w.mask = 1
+		} else {
+			w.mask = w.mask<<shift | 1
+		}
+		w.max = seq
+		return
+	}
+
+	if d := w.max - seq; d < kSeqWindowSize {
+		w.mask |= 1 << d
+	}
+}
@@ -0,0 +1,238 @@
+package game
+
+import (
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+const kSimQueueSize = 1024 // 异步模拟最多积压多少帧
+
+// Simulation 服务端权威模拟，用和客户端一样的随机种子和帧跑一遍游戏逻辑
+type Simulation interface {
+	Step(frame *pb.FrameData) // 执行一帧，空帧的Input为空
+	Hash() uint64             // 当前状态hash
+	Result() *MatchResult     // 模拟出来的结算，nil表示还没结束
+	Close()
+}
+
+// simState 执行完一帧之后的状态
+type simState struct {
+	frameID uint32
+	hash    uint64
+	result  *MatchResult
+}
+
+// simRunner 驱动Simulation，比较服务端和客户端的状态hash
+type simRunner struct {
+	sim   Simulation
+	async bool
+
+	in      chan *pb.FrameData
+	out     chan simState
+	quit    chan struct{}
+	done    chan struct{}
+	lagging bool // 异步模拟跟不上，丢过帧，之后的状态都不可信
+
+	hashes       map[uint32]uint64            // frameID->服务端hash
+	clientHashes map[uint32]map[uint64]uint64 // frameID->playerID->客户端hash
+	lastFrame    uint32
+	result       *MatchResult
+	resultFrame  uint32 // 模拟结束的帧
+}
+
+func newSimRunner(sim Simulation, async bool) *simRunner {
+	s := &simRunner{
+		sim:          sim,
+		async:        async,
+		hashes:       make(map[uint32]uint64),
+		clientHashes: make(map[uint32]map[uint64]uint64),
+	}
+
+	if async {
+		s.in = make(chan *pb.FrameData, kSimQueueSize)
+		s.out = make(chan simState, kSimQueueSize)
+		s.quit = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.run()
+	}
+
+	return s
+}
+
+// run 异步模拟的goroutine，out满了也能被close停掉
+func (s *simRunner) run() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.quit:
+			return
+		case f := <-s.in:
+			st := s.step(f)
+			select {
+			case s.out <- st:
+			case <-s.quit:
+				return
+			}
+		}
+	}
+}
+
+// push 把帧交给异步模拟，积压满了不等，标记成跟不上
+func (s *simRunner) push(f *pb.FrameData) bool {
+	select {
+	case s.in <- f:
+		return true
+	default:
+		s.lagging = true
+		return false
+	}
+}
+
+func (s *simRunner) step(f *pb.FrameData) simState {
+	s.sim.Step(f)
+	return simState{
+		frameID: f.GetFrameID(),
+		hash:    s.sim.Hash(),
+		result:  s.sim.Result(),
+	}
+}
+
+func (s *simRunner) close() {
+	if s.async {
+		close(s.quit)
+		<-s.done
+	}
+	s.sim.Close()
+}
+
+// newSimulation 创建房间的权威模拟，座位顺序的玩家列表
+func (g *Game) newSimulation() {
+	if nil == g.cfg.NewSimulation {
+		return
+	}
+
+	stats := g.PlayerStats()
+	players := make([]uint64, 0, len(stats))
+	for _, v := range stats {
+		players = append(players, v.ID)
+	}
+
+	sim, err := g.cfg.NewSimulation(g.id, g.randomSeed, players)
+	if nil != err {
+		l4g.Error("[game(%d)] create simulation error:[%s]", g.id, err.Error())
+		return
+	}
+
+	g.sim = newSimRunner(sim, g.cfg.SimulationAsync)
+}
+
+// stepSimulation 把刚结束的帧交给模拟，处理模拟的结果
+func (g *Game) stepSimulation() {
+	if nil == g.sim || nil != g.sim.result {
+		return
+	}
+
+	idx := g.logic.getFrameCount() - 1
+	f := g.logic.getFrame(idx)
+	if nil == f {
+		f = &pb.FrameData{FrameID: proto.Uint32(idx)}
+	}
+
+	if !g.sim.async {
+		g.onSimState(g.sim.step(f))
+		return
+	}
+
+	// 跟不上之后不再送帧，只处理之前已经交给模拟的帧
+	if !g.sim.lagging && !g.sim.push(f) {
+		l4g.Error("[game(%d)] simulation lagging, drop frame[%d], stop checking hash", g.id, idx)
+	}
+	for {
+		select {
+		case st := <-g.sim.out:
+			g.onSimState(st)
+		default:
+			return
+		}
+	}
+}
+
+func (g *Game) onSimState(st simState) {
+	s := g.sim
+	s.hashes[st.frameID] = st.hash
+	s.lastFrame = st.frameID
+
+	for id, h := range s.clientHashes[st.frameID] {
+		g.compareHash(id, st.frameID, st.hash, h)
+	}
+	delete(s.clientHashes, st.frameID)
+
+	// 只保留最近SimHashWindow帧
+	if st.frameID >= g.cfg.SimHashWindow {
+		old := st.frameID - g.cfg.SimHashWindow
+		delete(s.hashes, old)
+		delete(s.clientHashes, old)
+	}
+
+	if nil != st.result && nil == s.result {
+		s.result = st.result
+		s.resultFrame = st.frameID
+		l4g.Warn("[game(%d)] simulation over at frame[%d] winner=[%d]", g.id, st.frameID, st.result.WinnerID)
+	}
+}
+
+// doHash 客户端上报状态hash
+func (g *Game) doHash(p *Player, msg *pb_packet.Packet) {
+	if nil == g.sim || g.sim.lagging {
+		return
+	}
+
+	m := &pb.C2S_HashMsg{}
+	if err := msg.Unmarshal(m); nil != err {
+		l4g.Error("[game(%d)] doHash player[%d] UnmarshalPB error:[%s]", g.id, p.id, err.Error())
+		return
+	}
+
+	idx := m.GetFrameID()
+	if h, ok := g.sim.hashes[idx]; ok {
+		g.compareHash(p.id, idx, h, m.GetHash())
+		return
+	}
+
+	// 模拟还没跑到这一帧(异步)，先存起来
+	if idx > g.sim.lastFrame && idx < g.logic.getFrameCount() {
+		c, ok := g.sim.clientHashes[idx]
+		if !ok {
+			c = make(map[uint64]uint64)
+			g.sim.clientHashes[idx] = c
+		}
+		c[p.id] = m.GetHash()
+	}
+}
+
+func (g *Game) compareHash(id uint64, idx uint32, hash, clientHash uint64) {
+	if hash == clientHash {
+		return
+	}
+
+	p, ok := g.players[id]
+	if !ok {
+		return
+	}
+
+	p.desyncCount++
+	l4g.Error("[game(%d)] player[%d] desync at frame[%d] hash=[%x] client=[%x]", g.id, id, idx, hash, clientHash)
+	p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Hash), &pb.S2C_HashMsg{
+		FrameID:    proto.Uint32(idx),
+		Hash:       proto.Uint64(hash),
+		ClientHash: proto.Uint64(clientHash),
+	}))
+}
+
+// simOver 模拟已经结束并且结束帧都发给客户端了
+func (g *Game) simOver() bool {
+	return nil != g.sim && nil != g.sim.result && g.clientFrameCount > g.sim.resultFrame
+}
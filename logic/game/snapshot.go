@@ -0,0 +1,166 @@
+package game
+
+import (
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+const (
+	kSnapshotMsgOverhead = 128 // 分片消息里除了data之外的字段最多占多少字节(实际不到40)
+
+	// 下发快照每个分片的大小，整个消息不能超过pb_packet.MaxPacketLen，不然客户端读包会出错断开
+	kSnapshotChunkSize     = pb_packet.MaxPacketLen - pb_packet.MinPacketLen - kSnapshotMsgOverhead
+	kMaxSnapshotCandidates = 4 // 最多同时保留多少个还没达成一致的快照帧
+)
+
+// snapshot 客户端上传的状态快照
+type snapshot struct {
+	frameID uint32
+	hash    uint64
+	data    []byte
+	players map[uint64]bool // 上传了相同帧和hash的玩家
+}
+
+// snapshotUpload 正在上传的快照
+type snapshotUpload struct {
+	frameID uint32
+	hash    uint64
+	total   uint32
+	buf     []byte
+}
+
+// doSnapshot 接收客户端上传的快照分片，收完之后投票
+func (g *Game) doSnapshot(p *Player, msg *pb_packet.Packet) {
+	if !g.isPlaying() {
+		return
+	}
+
+	m := &pb.C2S_SnapshotMsg{}
+	if err := msg.Unmarshal(m); nil != err {
+		l4g.Error("[game(%d)] doSnapshot player[%d] UnmarshalPB error:[%s]", g.id, p.id, err.Error())
+		return
+	}
+
+	if 0 == m.GetTotal() || m.GetTotal() > g.cfg.SnapshotMaxSize || m.GetFrameID() >= g.logic.getFrameCount() {
+		l4g.Warn("[game(%d)] player[%d] snapshot frame[%d] size[%d] invalid", g.id, p.id, m.GetFrameID(), m.GetTotal())
+		p.snapUpload = nil
+		return
+	}
+
+	// 新的快照从头开始
+	u := p.snapUpload
+	if 0 == m.GetOffset() {
+		u = &snapshotUpload{
+			frameID: m.GetFrameID(),
+			hash:    m.GetHash(),
+			total:   m.GetTotal(),
+			buf:     make([]byte, 0, m.GetTotal()),
+		}
+		p.snapUpload = u
+	}
+
+	if nil == u || u.frameID != m.GetFrameID() || u.hash != m.GetHash() || u.total != m.GetTotal() ||
+		int(m.GetOffset()) != len(u.buf) || len(u.buf)+len(m.GetData()) > int(u.total) {
+		l4g.Warn("[game(%d)] player[%d] snapshot frame[%d] chunk[%d] out of order", g.id, p.id, m.GetFrameID(), m.GetOffset())
+		p.snapUpload = nil
+		return
+	}
+
+	u.buf = append(u.buf, m.GetData()...)
+	if len(u.buf) < int(u.total) {
+		return
+	}
+
+	p.snapUpload = nil
+	g.voteSnapshot(p, u)
+}
+
+// voteSnapshot 相同帧和hash的快照达到SnapshotQuorum个玩家就采用
+func (g *Game) voteSnapshot(p *Player, u *snapshotUpload) {
+	if nil != g.snapshot && u.frameID <= g.snapshot.frameID {
+		return
+	}
+
+	// 有权威模拟的先和模拟的hash比较
+	if nil != g.sim {
+		if h, ok := g.sim.hashes[u.frameID]; ok && h != u.hash {
+			l4g.Warn("[game(%d)] player[%d] snapshot frame[%d] hash mismatch", g.id, p.id, u.frameID)
+			return
+		}
+	}
+
+	key := snapshotKey{u.frameID, u.hash}
+	s, ok := g.snapCandidates[key]
+	if !ok {
+		if len(g.snapCandidates) >= kMaxSnapshotCandidates {
+			g.evictSnapshotCandidate()
+		}
+		s = &snapshot{
+			frameID: u.frameID,
+			hash:    u.hash,
+			data:    u.buf,
+			players: make(map[uint64]bool),
+		}
+		g.snapCandidates[key] = s
+	}
+	s.players[p.id] = true
+
+	if len(s.players) < g.cfg.snapshotQuorum() {
+		return
+	}
+
+	g.snapshot = s
+	for k := range g.snapCandidates {
+		if k.frameID <= s.frameID {
+			delete(g.snapCandidates, k)
+		}
+	}
+	l4g.Info("[game(%d)] snapshot frame[%d] hash[%x] size[%d] agreed by %d players", g.id, s.frameID, s.hash, len(s.data), len(s.players))
+}
+
+// evictSnapshotCandidate 删掉帧最早的候选快照
+func (g *Game) evictSnapshotCandidate() {
+	var oldest *snapshotKey
+	for k := range g.snapCandidates {
+		if nil == oldest || k.frameID < oldest.frameID {
+			k := k
+			oldest = &k
+		}
+	}
+	if nil != oldest {
+		delete(g.snapCandidates, *oldest)
+	}
+}
+
+type snapshotKey struct {
+	frameID uint32
+	hash    uint64
+}
+
+// sendSnapshot 把快照分片发给玩家，返回之后要从哪一帧开始发
+func (g *Game) sendSnapshot(p *Player, to uint32) uint32 {
+	s := g.snapshot
+	if nil == s || s.frameID >= to {
+		return 0
+	}
+
+	total := uint32(len(s.data))
+	for offset := uint32(0); offset < total; offset += kSnapshotChunkSize {
+		end := offset + kSnapshotChunkSize
+		if end > total {
+			end = total
+		}
+		p.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Snapshot), &pb.S2C_SnapshotMsg{
+			FrameID: proto.Uint32(s.frameID),
+			Hash:    proto.Uint64(s.hash),
+			Total:   proto.Uint32(total),
+			Offset:  proto.Uint32(offset),
+			Data:    s.data[offset:end],
+		}))
+	}
+
+	return s.frameID + 1
+}
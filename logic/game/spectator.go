@@ -0,0 +1,148 @@
+package game
+
+import (
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/network"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// JoinSpectator 观战者加入，观战者座位号为0，不参与准备和结束判断。返回false时连接要断开
+func (g *Game) JoinSpectator(id uint64, conn *network.Conn) bool {
+
+	msg := &pb.S2C_ConnectMsg{
+		ErrorCode: pb.ERRORCODE_ERR_Ok.Enum(),
+	}
+
+	if k_Ready != g.State && !g.isPlaying() {
+		msg.ErrorCode = pb.ERRORCODE_ERR_RoomState.Enum()
+		conn.AsyncWritePacket(pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), msg), 0)
+		l4g.Error("[game(%d)] spectator[%d] game is over", g.id, id)
+		return false
+	}
+
+	s, ok := g.spectators[id]
+	if !ok {
+		if len(g.spectators) >= int(g.cfg.MaxSpectators) {
+			msg.ErrorCode = pb.ERRORCODE_ERR_SpectatorFull.Enum()
+			conn.AsyncWritePacket(pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), msg), 0)
+			l4g.Error("[game(%d)] spectator[%d] spectators are full", g.id, id)
+			return false
+		}
+		s = NewPlayer(id, 0)
+	}
+
+	// 把现有的顶掉
+	if nil != s.client {
+		s.client.PutExtraData(nil)
+		l4g.Error("[game(%d)] spectator[%d] replace", g.id, id)
+	}
+
+	s.Connect(conn)
+	g.spectators[id] = s
+
+	s.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), msg))
+
+	return true
+}
+
+// LeaveSpectator 观战者离开
+func (g *Game) LeaveSpectator(id uint64) bool {
+	s, ok := g.spectators[id]
+	if !ok {
+		return false
+	}
+
+	s.Cleanup()
+	delete(g.spectators, id)
+
+	return true
+}
+
+// SpectatorCount 观战人数
+func (g *Game) SpectatorCount() int {
+	return len(g.spectators)
+}
+
+// ProcessSpectatorMsg 处理观战者的消息，观战者只能收不能操作
+func (g *Game) ProcessSpectatorMsg(id uint64, msg *pb_packet.Packet) {
+
+	s, ok := g.spectators[id]
+	if !ok {
+		l4g.Error("[game(%d)] processSpectatorMsg no spectator[%d] msg=[%d]", g.id, id, msg.GetMessageID())
+		return
+	}
+
+	switch pb.ID(msg.GetMessageID()) {
+	case pb.ID_MSG_JoinRoom:
+		g.doJoinRoom(s)
+	case pb.ID_MSG_Heartbeat:
+		s.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Heartbeat), nil))
+		s.RefreshHeartbeatTime()
+	case pb.ID_MSG_Ping:
+		g.doPing(s, msg)
+	case pb.ID_MSG_Ready:
+		if s.isReady {
+			break
+		}
+		g.doReady(s)
+		if g.isPlaying() {
+			g.startSpectator(s)
+		}
+	default:
+		l4g.Warn("[game(%d)] spectator[%d] msg=[%d] ignored", g.id, id, msg.GetMessageID())
+	}
+}
+
+// startSpectator 给观战者发开始消息和延迟之前的所有帧
+func (g *Game) startSpectator(s *Player) {
+	s.SendMessage(pb_packet.NewPacket(uint8(pb.ID_MSG_Start), g.startMessage()))
+	n := g.spectatorFrameCount()
+	s.SetSendFrameCount(g.sendSnapshot(s, n))
+	g.sendSpectatorFrames(s, n, true)
+}
+
+// spectatorFrameCount 观战者能看到的帧数(延迟SpectatorDelayFrames)
+func (g *Game) spectatorFrameCount() uint32 {
+	n := g.logic.getFrameCount()
+	if n <= g.cfg.SpectatorDelayFrames {
+		return 0
+	}
+	return n - g.cfg.SpectatorDelayFrames
+}
+
+func (g *Game) broadcastSpectatorFrames() {
+	framesCount := g.spectatorFrameCount()
+	for _, s := range g.spectators {
+		g.sendSpectatorFrames(s, framesCount, false)
+	}
+}
+
+// sendSpectatorFrames 攒够BroadcastOffsetFrames帧再发，force为true时立刻发
+func (g *Game) sendSpectatorFrames(s *Player, framesCount uint32, force bool) {
+	if !s.IsOnline() || !s.isReady {
+		return
+	}
+
+	from := s.GetSendFrameCount()
+	if from >= framesCount || (!force && framesCount-from < BroadcastOffsetFrames) {
+		return
+	}
+
+	g.sendFrames(s, from, framesCount)
+	s.SetSendFrameCount(framesCount)
+}
+
+// flushSpectators 游戏结束时把剩下的帧都发给观战者
+func (g *Game) flushSpectators() {
+	for _, s := range g.spectators {
+		g.sendSpectatorFrames(s, g.logic.getFrameCount(), true)
+	}
+}
+
+func (g *Game) broadcastSpectators(msg network.Packet) {
+	for _, s := range g.spectators {
+		s.SendMessage(msg)
+	}
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// LockstepMode 帧同步模式
+type LockstepMode int
+
+const (
+	LockstepRelay LockstepMode = 0 // 固定帧率，每个tick结束一帧
+	LockstepTurn  LockstepMode = 1 // 回合制，所有在线玩家都输入了或者超时才结束一帧
+)
+
+// TurnTimeoutPolicy 回合制模式下一帧超时的处理方式
+type TurnTimeoutPolicy int
+
+const (
+	TurnTimeoutSkip  TurnTimeoutPolicy = 0 // 直接结束这一帧
+	TurnTimeoutEvent TurnTimeoutPolicy = 1 // 给没输入的玩家插入EVT_TurnTimeout再结束
+)
+
+// frameReady 当前帧是否可以结束
+func (g *Game) frameReady(now time.Time) bool {
+	if LockstepTurn != g.cfg.LockstepMode {
+		return true
+	}
+
+	idx := g.logic.getFrameCount()
+	waiting := g.turnWaiting(idx)
+	if len(waiting) > 0 {
+		if 0 == g.cfg.TurnTimeout || now.Sub(g.turnStart) < g.cfg.TurnTimeout {
+			return false
+		}
+
+		l4g.Warn("[game(%d)] turn[%d] timeout waiting[%d]", g.id, idx, len(waiting))
+		if TurnTimeoutEvent == g.cfg.TurnTimeoutPolicy {
+			for _, p := range waiting {
+				g.pushEvent(p, pb.EVENT_EVT_TurnTimeout)
+			}
+		}
+	}
+
+	// 回合结束马上发出去
+	g.turnStart = now
+	g.dirty = true
+	return true
+}
+
+// turnWaiting 还没有输入第idx帧的在线玩家
+func (g *Game) turnWaiting(idx uint32) []*Player {
+	var ret []*Player
+	for _, p := range g.players {
+		if !p.isOnline || !p.isReady || p.surrendered || p.left {
+			continue
+		}
+		if !g.logic.hasInput(idx, p.id) {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+func (m LockstepMode) pb() pb.MODE {
+	if LockstepTurn == m {
+		return pb.MODE_MODE_Turn
+	}
+	return pb.MODE_MODE_Relay
+}
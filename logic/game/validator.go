@@ -0,0 +1,168 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// InputVerdict 输入校验结果
+type InputVerdict int
+
+const (
+	InputAccept    InputVerdict = 0 // 原样接受
+	InputTransform InputVerdict = 1 // 改写之后接受(比如映射操作id)，不算违规
+	InputClamp     InputVerdict = 2 // 修正之后接受(比如坐标越界)，算一次违规
+	InputReject    InputVerdict = 3 // 丢弃，算一次违规
+)
+
+// InputContext 输入的上下文
+type InputContext struct {
+	PlayerID   uint64
+	Seat       int32
+	FrameID    uint32 // 输入的目标帧
+	CurFrameID uint32 // 服务端当前帧
+}
+
+// InputValidator 服务端输入校验，在输入进帧之前调用，可以直接修改cmd
+// 每个房间一个实例，只在房间的goroutine里调用
+type InputValidator interface {
+	Validate(ctx *InputContext, cmd *pb.InputData) InputVerdict
+}
+
+// InputValidatorFunc 函数形式的InputValidator
+type InputValidatorFunc func(ctx *InputContext, cmd *pb.InputData) InputVerdict
+
+// Validate 实现InputValidator
+func (f InputValidatorFunc) Validate(ctx *InputContext, cmd *pb.InputData) InputVerdict {
+	return f(ctx, cmd)
+}
+
+// ChainValidator 依次校验，遇到InputReject就停，返回最严重的结果
+type ChainValidator []InputValidator
+
+// Validate 实现InputValidator
+func (c ChainValidator) Validate(ctx *InputContext, cmd *pb.InputData) InputVerdict {
+	ret := InputAccept
+	for _, v := range c {
+		r := v.Validate(ctx, cmd)
+		if r > ret {
+			ret = r
+		}
+		if InputReject == ret {
+			break
+		}
+	}
+	return ret
+}
+
+// RangeValidator 把坐标钳制到[MinX,MaxX] [MinY,MaxY]
+type RangeValidator struct {
+	MinX, MaxX int32
+	MinY, MaxY int32
+}
+
+// Validate 实现InputValidator
+func (v *RangeValidator) Validate(ctx *InputContext, cmd *pb.InputData) InputVerdict {
+	x, y := clamp(cmd.GetX(), v.MinX, v.MaxX), clamp(cmd.GetY(), v.MinY, v.MaxY)
+	if x == cmd.GetX() && y == cmd.GetY() {
+		return InputAccept
+	}
+	cmd.X, cmd.Y = &x, &y
+	return InputClamp
+}
+
+// SkillValidator 操作id白名单，Allowed返回false的丢弃
+type SkillValidator struct {
+	Allowed func(playerID uint64, seat int32, sid int32) bool
+}
+
+// Validate 实现InputValidator
+func (v *SkillValidator) Validate(ctx *InputContext, cmd *pb.InputData) InputVerdict {
+	if v.Allowed(ctx.PlayerID, ctx.Seat, cmd.GetSid()) {
+		return InputAccept
+	}
+	return InputReject
+}
+
+// RateValidator 每个玩家每秒最多MaxPerSecond个输入
+type RateValidator struct {
+	MaxPerSecond uint32
+
+	windows map[uint64]*rateLimiter
+}
+
+// NewRateValidator 构造
+func NewRateValidator(maxPerSecond uint32) *RateValidator {
+	return &RateValidator{
+		MaxPerSecond: maxPerSecond,
+		windows:      make(map[uint64]*rateLimiter),
+	}
+}
+
+// Validate 实现InputValidator
+func (v *RateValidator) Validate(ctx *InputContext, cmd *pb.InputData) InputVerdict {
+	w, ok := v.windows[ctx.PlayerID]
+	if !ok {
+		w = &rateLimiter{}
+		v.windows[ctx.PlayerID] = w
+	}
+
+	if !w.allow(time.Now(), v.MaxPerSecond) {
+		return InputReject
+	}
+	return InputAccept
+}
+
+func clamp(v, min, max int32) int32 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// validateInput 校验输入，返回false表示丢弃
+func (g *Game) validateInput(p *Player, idx uint32, cmd *pb.InputData) bool {
+	if nil == g.validator {
+		return true
+	}
+
+	ctx := &InputContext{
+		PlayerID:   p.id,
+		Seat:       p.idx,
+		FrameID:    idx,
+		CurFrameID: g.logic.getFrameCount(),
+	}
+
+	switch g.validator.Validate(ctx, cmd) {
+	case InputAccept, InputTransform:
+		return true
+	case InputClamp:
+		p.inputStats.Clamped++
+		g.onInputViolation(p)
+		// 这次违规被踢掉的，输入不能再进离开之后的帧
+		return !p.left
+	default:
+		p.inputStats.Rejected++
+		g.onInputViolation(p)
+		return false
+	}
+}
+
+// onInputViolation 违规次数超过MaxInputViolations就踢掉
+func (g *Game) onInputViolation(p *Player) {
+	n := p.inputStats.Clamped + p.inputStats.Rejected
+	l4g.Warn("[game(%d)] player[%d] input violation[%d]", g.id, p.id, n)
+
+	if 0 == g.cfg.MaxInputViolations || n < uint64(g.cfg.MaxInputViolations) || p.left {
+		return
+	}
+
+	g.Kick(p.id, 0, fmt.Sprintf("input violations[%d]", n), g.cfg.KickCooldown)
+}
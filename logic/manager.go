@@ -4,24 +4,181 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/byebyebruce/lockstepserver/logic/game"
 	"github.com/byebyebruce/lockstepserver/logic/room"
+
+	l4g "github.com/alecthomas/log4go"
 )
 
+const kMaxReports = 1024 // 保留最近结束的房间结算数量
+
 // RoomManager 房间管理器
 type RoomManager struct {
-	room map[uint64]*room.Room
-	wg   sync.WaitGroup
-	rw   sync.RWMutex
+	room        map[uint64]*room.Room
+	config      *game.Config
+	types       map[int32]*RoomType
+	validators  map[string]func(id uint64) game.InputValidator
+	typeChecks  map[int32]func(id uint64) game.InputValidator
+	sims        map[int32]func(id uint64, seed int32, players []uint64) (game.Simulation, error)
+	sink        room.ResultSink
+	sched       *Scheduler
+	crash       *room.CrashConfig
+	checkpoints room.CheckpointStore
+	cpFrames    map[uint64]uint32 // roomID->已经存档到哪一帧
+	cpExit      chan struct{}
+	cpWg        sync.WaitGroup
+	reports     map[uint64]*room.Report
+	order       []uint64
+	wg          sync.WaitGroup
+	rw          sync.RWMutex
 }
 
 // NewRoomManager 构造
 func NewRoomManager() *RoomManager {
 	m := &RoomManager{
-		room: make(map[uint64]*room.Room),
+		room:       make(map[uint64]*room.Room),
+		config:     game.DefaultConfig(),
+		reports:    make(map[uint64]*room.Report),
+		types:      make(map[int32]*RoomType),
+		validators: make(map[string]func(id uint64) game.InputValidator),
+		typeChecks: make(map[int32]func(id uint64) game.InputValidator),
+		sims:       make(map[int32]func(id uint64, seed int32, players []uint64) (game.Simulation, error)),
+		cpFrames:   make(map[uint64]uint32),
 	}
+	// 默认类型，全部用默认配置
+	m.types[0] = &RoomType{Name: "default"}
 	return m
 }
 
+// SetGameConfig 设置之后创建的房间使用的游戏配置
+func (m *RoomManager) SetGameConfig(cfg *game.Config) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	m.config = cfg
+}
+
+// RegisterInputValidator 给房间类型注册输入校验，在类型的Validators之后执行，f每个房间调用一次
+func (m *RoomManager) RegisterInputValidator(typeID int32, f func(id uint64) game.InputValidator) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	m.typeChecks[typeID] = f
+}
+
+// RegisterNamedValidator 注册一个输入校验，房间类型的Validators里用名字引用，f每个房间调用一次
+func (m *RoomManager) RegisterNamedValidator(name string, f func(id uint64) game.InputValidator) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	m.validators[name] = f
+}
+
+// RegisterRoomType 注册房间类型，已经有的覆盖。引用的校验要先注册
+func (m *RoomManager) RegisterRoomType(t *RoomType) error {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	if _, err := t.config(m.config, m.validators); nil != err {
+		return err
+	}
+	m.types[t.ID] = t
+
+	return nil
+}
+
+// LoadRoomTypes 从json配置文件注册房间类型，有一个不对就都不注册
+func (m *RoomManager) LoadRoomTypes(file string) error {
+	types, err := LoadRoomTypes(file)
+	if nil != err {
+		return err
+	}
+
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	for _, t := range types {
+		if _, err := t.config(m.config, m.validators); nil != err {
+			return err
+		}
+	}
+	for _, t := range types {
+		m.types[t.ID] = t
+	}
+
+	return nil
+}
+
+// RoomType 获得房间类型
+func (m *RoomManager) RoomType(typeID int32) *RoomType {
+	m.rw.RLock()
+	defer m.rw.RUnlock()
+
+	return m.types[typeID]
+}
+
+// RegisterSimulation 给房间类型注册服务端权威模拟，f每个房间开始时调用一次
+func (m *RoomManager) RegisterSimulation(typeID int32, f func(id uint64, seed int32, players []uint64) (game.Simulation, error)) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	m.sims[typeID] = f
+}
+
+// UseScheduler 之后创建的房间由共享的调度器驱动，不再每个房间一个goroutine，要在创建房间之前调用
+func (m *RoomManager) UseScheduler(workers int) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	if nil == m.sched {
+		m.sched = NewScheduler(workers)
+	}
+}
+
+// SetResultSink 设置房间结算报告的去处(webhook、文件等)
+func (m *RoomManager) SetResultSink(sink room.ResultSink) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	m.sink = sink
+}
+
+// SetCrashConfig 设置房间panic的处理，之后创建的房间生效
+func (m *RoomManager) SetCrashConfig(c *room.CrashConfig) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	m.crash = c
+}
+
+// GetReport 获得最近结束的房间的结算
+func (m *RoomManager) GetReport(id uint64) *room.Report {
+	m.rw.RLock()
+	defer m.rw.RUnlock()
+
+	return m.reports[id]
+}
+
+// onReport 保留结算给管理接口查询，再交给外部的sink
+func (m *RoomManager) onReport(r *room.Report) error {
+	m.rw.Lock()
+	if _, ok := m.reports[r.RoomID]; !ok {
+		m.order = append(m.order, r.RoomID)
+	}
+	m.reports[r.RoomID] = r
+	for len(m.order) > kMaxReports {
+		delete(m.reports, m.order[0])
+		m.order = m.order[1:]
+	}
+	sink := m.sink
+	m.rw.Unlock()
+
+	if nil == sink {
+		return nil
+	}
+	return sink.Report(r)
+}
+
 // CreateRoom 创建房间
 func (m *RoomManager) CreateRoom(id uint64, typeID int32, playerID []uint64, randomSeed int32, logicServer string) (*room.Room, error) {
 	m.rw.Lock()
@@ -32,23 +189,80 @@ func (m *RoomManager) CreateRoom(id uint64, typeID int32, playerID []uint64, ran
 		return nil, fmt.Errorf("room id[%d] exists", id)
 	}
 
-	r = room.NewRoom(id, typeID, playerID, randomSeed, logicServer)
+	cfg, err := m.typeConfig(typeID)
+	if nil != err {
+		return nil, err
+	}
+	if cfg.MaxPlayers > 0 && len(playerID) > int(cfg.MaxPlayers) {
+		return nil, fmt.Errorf("room type[%d] max players[%d] but got [%d]", typeID, cfg.MaxPlayers, len(playerID))
+	}
+
+	r = room.NewRoom(id, typeID, playerID, randomSeed, logicServer, cfg)
+	m.startRoom(r)
+
+	return r, nil
+}
+
+// typeConfig 房间类型对应的游戏配置，要先加锁
+func (m *RoomManager) typeConfig(typeID int32) (*game.Config, error) {
+	t, ok := m.types[typeID]
+	if !ok {
+		return nil, fmt.Errorf("room type[%d] not found", typeID)
+	}
+	cfg, err := t.config(m.config, m.validators)
+	if nil != err {
+		return nil, err
+	}
+	if sim, ok := m.sims[typeID]; ok {
+		cfg.NewSimulation = sim
+	}
+	if f, ok := m.typeChecks[typeID]; ok {
+		prev := cfg.NewInputValidator
+		cfg.NewInputValidator = func(id uint64) game.InputValidator {
+			if nil == prev {
+				return f(id)
+			}
+			return game.ChainValidator{prev(id), f(id)}
+		}
+	}
+	return cfg, nil
+}
+
+// startRoom 开始驱动房间，要先加锁
+func (m *RoomManager) startRoom(r *room.Room) {
+	id := r.ID()
+	r.SetResultSink(room.ResultSinkFunc(m.onReport))
+	r.SetCrashConfig(m.crash)
 	m.room[id] = r
 
+	m.wg.Add(1)
+	done := func() {
+		m.rw.Lock()
+		delete(m.room, id)
+		delete(m.cpFrames, id)
+		store := m.checkpoints
+		m.rw.Unlock()
+
+		// 结束(正常结束、超时、出错)的删掉存档，只有停服强制退出的留着重启之后恢复
+		if nil != store && r.IsOver() {
+			if err := store.Delete(id); nil != err {
+				l4g.Error("[room(%d)] delete checkpoint error:[%s]", id, err.Error())
+			}
+		}
+
+		m.wg.Done()
+	}
+
+	if nil != m.sched {
+		m.sched.Add(r, done)
+		return
+	}
+
 	go func() {
-		m.wg.Add(1)
-		defer func() {
-			m.rw.Lock()
-			delete(m.room, id)
-			m.rw.Unlock()
-
-			m.wg.Done()
-		}()
+		defer done()
 		r.Run()
 
 	}()
-
-	return r, nil
 }
 
 // GetRoom 获得房间
@@ -70,15 +284,27 @@ func (m *RoomManager) RoomNum() int {
 	return len(m.room)
 }
 
-// Stop 停止
+// Stop 停止，开了存档的先给所有房间存一次
 func (m *RoomManager) Stop() {
+	m.stopCheckpoint()
 
+	// 房间退出时上报结算要拿锁，不能拿着锁等房间退出
 	m.rw.Lock()
+	rooms := make([]*room.Room, 0, len(m.room))
 	for _, v := range m.room {
-		v.Stop()
+		rooms = append(rooms, v)
 	}
 	m.room = make(map[uint64]*room.Room)
+	sched := m.sched
 	m.rw.Unlock()
 
+	for _, v := range rooms {
+		v.Stop()
+	}
+
 	m.wg.Wait()
+
+	if nil != sched {
+		sched.Stop()
+	}
 }
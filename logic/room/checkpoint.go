@@ -0,0 +1,206 @@
+package room
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// Checkpoint 房间存档，服务重启之后用来恢复。游戏配置不存，恢复时按TypeID重新生成
+type Checkpoint struct {
+	RoomID      uint64
+	TypeID      int32
+	TimeStamp   int64
+	SecretKey   string
+	SpectToken  string
+	LogicServer string
+	Players     []uint64
+	Game        *game.Checkpoint
+}
+
+// CheckpointStore 房间存档的存储
+type CheckpointStore interface {
+	// Save 保存存档，同一个房间的覆盖。Game.Frames只有Game.FromFrame之后的帧，要接在之前存的帧后面
+	Save(*Checkpoint) error
+	// Delete 房间结束之后删除存档
+	Delete(roomID uint64) error
+	// Load 读取所有存档，Game.Frames是从第0帧开始的所有帧
+	Load() ([]*Checkpoint, error)
+}
+
+// Checkpoint 在房间的goroutine里生成存档，只带from之后的帧，房间已经结束返回false
+func (r *Room) Checkpoint(from uint32) (*Checkpoint, bool) {
+	if r.IsOver() {
+		return nil, false
+	}
+
+	var cp *Checkpoint
+	ok := r.call(func() {
+		r.playersRW.RLock()
+		players := append([]uint64(nil), r.players...)
+		r.playersRW.RUnlock()
+
+		cp = &Checkpoint{
+			RoomID:      r.roomID,
+			TypeID:      r.typeID,
+			TimeStamp:   r.timeStamp,
+			SecretKey:   r.secretKey,
+			SpectToken:  r.spectToken,
+			LogicServer: r.logicServer,
+			Players:     players,
+			Game:        r.game.Checkpoint(from),
+		}
+	})
+
+	return cp, ok && nil != cp
+}
+
+// RestoreRoom 从存档恢复房间，之后和新建的房间一样调用Run或者Drive
+func RestoreRoom(cp *Checkpoint, cfg *game.Config) (*Room, error) {
+	if nil == cp.Game {
+		return nil, fmt.Errorf("room[%d] checkpoint has no game", cp.RoomID)
+	}
+	if nil == cfg {
+		cfg = game.DefaultConfig()
+	}
+
+	r := newRoom(cp.RoomID, cp.TypeID, cp.Players, cp.LogicServer, cfg)
+	r.timeStamp = cp.TimeStamp
+	r.secretKey = cp.SecretKey
+	r.spectToken = cp.SpectToken
+
+	g, err := game.RestoreGame(cp.RoomID, cp.Game, cfg, r)
+	if nil != err {
+		return nil, err
+	}
+	r.game = g
+
+	return r, nil
+}
+
+// DirCheckpointStore 每个房间一个json文件和一个只追加的帧文件。json先写临时文件再改名，写到一半挂掉不会弄坏旧的存档；
+// 帧文件每次只追加新的帧，json里只记录存到了哪一帧，多出来的帧读的时候忽略
+type DirCheckpointStore struct {
+	Dir string
+
+	logs map[uint64]*game.FrameLog
+	mu   sync.Mutex
+}
+
+func (s *DirCheckpointStore) fileName(roomID uint64) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("room_%d.json", roomID))
+}
+
+func (s *DirCheckpointStore) frameFileName(roomID uint64) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("room_%d.frames", roomID))
+}
+
+// frameLog 打开房间的帧文件，要先加锁
+func (s *DirCheckpointStore) frameLog(roomID uint64) (*game.FrameLog, error) {
+	if l, ok := s.logs[roomID]; ok {
+		return l, nil
+	}
+
+	l, err := game.OpenFrameLog(s.frameFileName(roomID))
+	if nil != err {
+		return nil, err
+	}
+	if nil == s.logs {
+		s.logs = make(map[uint64]*game.FrameLog)
+	}
+	s.logs[roomID] = l
+
+	return l, nil
+}
+
+// Save 实现CheckpointStore，先追加帧再写json
+func (s *DirCheckpointStore) Save(cp *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(cp)
+	if nil != err {
+		return err
+	}
+
+	if err := os.MkdirAll(s.Dir, 0755); nil != err {
+		return err
+	}
+
+	l, err := s.frameLog(cp.RoomID)
+	if nil != err {
+		return err
+	}
+	if err := l.Append(cp.Game.FromFrame, cp.Game.Frames); nil != err {
+		return err
+	}
+
+	name := s.fileName(cp.RoomID)
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); nil != err {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// Delete 实现CheckpointStore
+func (s *DirCheckpointStore) Delete(roomID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.logs[roomID]; ok {
+		delete(s.logs, roomID)
+		l.Remove()
+	}
+	for _, name := range []string{s.fileName(roomID), s.frameFileName(roomID)} {
+		if err := os.Remove(name); nil != err && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load 实现CheckpointStore，读不出来的存档跳过
+func (s *DirCheckpointStore) Load() ([]*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(s.Dir, "room_*.json"))
+	if nil != err {
+		return nil, err
+	}
+
+	ret := make([]*Checkpoint, 0, len(files))
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if nil != err {
+			l4g.Error("[checkpoint] read [%s] error:[%s]", f, err.Error())
+			continue
+		}
+
+		cp := &Checkpoint{}
+		if err := json.Unmarshal(b, cp); nil != err || nil == cp.Game {
+			l4g.Error("[checkpoint] parse [%s] error:[%v]", f, err)
+			continue
+		}
+
+		l, err := s.frameLog(cp.RoomID)
+		if nil == err {
+			cp.Game.Frames, err = l.Frames(cp.Game.FrameCount)
+		}
+		if nil != err {
+			l4g.Error("[checkpoint] read frames of [%s] error:[%s]", f, err.Error())
+			continue
+		}
+		ret = append(ret, cp)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,85 @@
+package room
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+func Test_DirCheckpointStore(t *testing.T) {
+	dir := t.TempDir()
+	store := &DirCheckpointStore{Dir: dir}
+
+	save := func(s *DirCheckpointStore, from, count uint32, ids ...uint32) {
+		cp := &Checkpoint{RoomID: 1, Game: &game.Checkpoint{FromFrame: from, FrameCount: count}}
+		for _, id := range ids {
+			cp.Game.Frames = append(cp.Game.Frames, &pb.FrameData{FrameID: proto.Uint32(id), Input: []*pb.InputData{{Sid: proto.Int32(int32(id))}}})
+		}
+		if err := s.Save(cp); nil != err {
+			t.Fatal(err)
+		}
+	}
+	load := func(s *DirCheckpointStore) []uint32 {
+		cps, err := s.Load()
+		if nil != err || len(cps) != 1 {
+			t.Fatalf("load error %v %d", err, len(cps))
+		}
+		var ids []uint32
+		for _, f := range cps[0].Game.Frames {
+			ids = append(ids, f.GetFrameID())
+		}
+		return ids
+	}
+	equal := func(a []uint32, b ...uint32) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	// 每次只追加新的帧，上次没存成功的重新存不会重复
+	save(store, 0, 3, 0, 2)
+	save(store, 3, 5, 3)
+	save(store, 3, 5, 3, 4)
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "room_1.json")); bytes.Contains(b, []byte("Frames")) {
+		t.Error("frames should not be in json")
+	}
+
+	// 重启之后读出所有帧，帧文件里json之后多出来的和写了一半的都不要
+	store = &DirCheckpointStore{Dir: dir}
+	if ids := load(store); !equal(ids, 0, 2, 3, 4) {
+		t.Fatalf("wrong frames %v", ids)
+	}
+	save(store, 5, 7, 5, 6)
+	store.logs[1].Append(7, []*pb.FrameData{{FrameID: proto.Uint32(7)}})
+	f, _ := os.OpenFile(filepath.Join(dir, "room_1.frames"), os.O_APPEND|os.O_WRONLY, 0644)
+	f.Write([]byte{0, 0, 0, 8, 0})
+	f.Close()
+
+	store = &DirCheckpointStore{Dir: dir}
+	if ids := load(store); !equal(ids, 0, 2, 3, 4, 5, 6) {
+		t.Fatalf("wrong frames after restart %v", ids)
+	}
+	save(store, 7, 8, 7)
+	if ids := load(&DirCheckpointStore{Dir: dir}); !equal(ids, 0, 2, 3, 4, 5, 6, 7) {
+		t.Fatalf("wrong frames after append %v", ids)
+	}
+
+	if err := store.Delete(1); nil != err {
+		t.Fatal(err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "room_1.*")); len(files) != 0 {
+		t.Errorf("checkpoint files should be deleted %v", files)
+	}
+}
@@ -0,0 +1,75 @@
+package room
+
+import (
+	"time"
+)
+
+const MaxCatchUpTicks = 5 // 落后时一次最多补多少个tick，更多的直接丢掉
+
+// TickStats tick调度统计
+type TickStats struct {
+	Ticks       uint64        // 执行的tick数
+	CatchUp     uint64        // 因为落后补执行的tick数
+	Dropped     uint64        // 落后太多丢掉的tick数
+	Lateness    time.Duration // 平滑后的tick延迟
+	MaxLateness time.Duration // 最大tick延迟
+	Jitter      time.Duration // tick延迟的平均偏差
+}
+
+// fixedStep 固定步长调度，按开始时间算出现在应该执行到第几个tick
+type fixedStep struct {
+	start time.Time
+	step  time.Duration
+	done  int64
+	stats TickStats
+}
+
+func newFixedStep(start time.Time, step time.Duration) *fixedStep {
+	return &fixedStep{
+		start: start,
+		step:  step,
+	}
+}
+
+// advance 返回现在应该执行几个tick
+func (f *fixedStep) advance(now time.Time) int {
+	due := int64(now.Sub(f.start)/f.step) - f.done
+	if due <= 0 {
+		return 0
+	}
+
+	// 最早那个没执行的tick晚了多久
+	f.update(now.Sub(f.start.Add(time.Duration(f.done+1) * f.step)))
+
+	if due > MaxCatchUpTicks {
+		f.stats.Dropped += uint64(due - MaxCatchUpTicks)
+		f.done += due - MaxCatchUpTicks
+		due = MaxCatchUpTicks
+	}
+
+	f.done += due
+	f.stats.Ticks += uint64(due)
+	f.stats.CatchUp += uint64(due - 1)
+
+	return int(due)
+}
+
+func (f *fixedStep) update(late time.Duration) {
+	s := &f.stats
+	if late > s.MaxLateness {
+		s.MaxLateness = late
+	}
+
+	if 0 == s.Ticks {
+		s.Lateness = late
+		s.Jitter = late / 2
+		return
+	}
+
+	d := s.Lateness - late
+	if d < 0 {
+		d = -d
+	}
+	s.Jitter = (3*s.Jitter + d) / 4
+	s.Lateness = (7*s.Lateness + late) / 8
+}
@@ -0,0 +1,38 @@
+package room
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_FixedStep(t *testing.T) {
+	start := time.Now()
+	step := time.Millisecond * 10
+	f := newFixedStep(start, step)
+
+	if n := f.advance(start.Add(step / 2)); n != 0 {
+		t.Fatalf("should not tick before step, got %d", n)
+	}
+	if n := f.advance(start.Add(step)); n != 1 {
+		t.Fatalf("should tick once, got %d", n)
+	}
+
+	// 晚了3个tick就补上
+	if n := f.advance(start.Add(step*4 + step/2)); n != 3 {
+		t.Fatalf("should catch up 3 ticks, got %d", n)
+	}
+	if f.stats.CatchUp != 2 || f.stats.Lateness == 0 || f.stats.MaxLateness != step*2+step/2 {
+		t.Errorf("stats error %+v", f.stats)
+	}
+
+	// 落后太多只补MaxCatchUpTicks个
+	if n := f.advance(start.Add(step * 20)); n != MaxCatchUpTicks {
+		t.Fatalf("should catch up at most %d ticks, got %d", MaxCatchUpTicks, n)
+	}
+	if f.stats.Dropped != 20-4-MaxCatchUpTicks || f.done != 20 {
+		t.Errorf("dropped error %+v done=%d", f.stats, f.done)
+	}
+	if n := f.advance(start.Add(step*21 + step/2)); n != 1 {
+		t.Fatalf("should tick once after drop, got %d", n)
+	}
+}
@@ -0,0 +1,185 @@
+package room
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+// CrashConfig 房间panic的处理，不设置时不recover，一个房间panic整个进程都会退出
+type CrashConfig struct {
+	Recover  bool   // recover之后只关掉出错的房间
+	DumpDir  string // 崩溃现场写到这个目录，为空不写
+	Messages int    // 现场里带最近多少个消息
+	Frames   uint32 // 现场里带最近多少帧
+}
+
+// MessageRecord 房间收到的一个消息
+type MessageRecord struct {
+	TimeMs    int64
+	ID        uint64
+	Spectator bool
+	MsgID     uint8
+	Data      []byte `json:",omitempty"`
+}
+
+// CrashDump 崩溃现场
+type CrashDump struct {
+	RoomID    uint64
+	TypeID    int32
+	TimeStamp int64 // 房间创建时间
+	CrashTime int64
+	Error     string
+	Stack     string
+	Game      *game.DebugState `json:",omitempty"`
+	Messages  []MessageRecord  // 最后一个是出错时正在处理的消息(如果是消息导致的)
+}
+
+// SetCrashConfig 设置panic的处理，要在Run之前调用
+func (r *Room) SetCrashConfig(c *CrashConfig) {
+	r.crash = c
+	if nil != c && c.Messages > 0 {
+		r.recent = make([]MessageRecord, 0, c.Messages)
+	}
+}
+
+// IsCrashed 是否因为panic关闭
+func (r *Room) IsCrashed() bool {
+	return atomic.LoadInt32(&r.crashFlag) != 0
+}
+
+func (r *Room) recoverable() bool {
+	return nil != r.crash && r.crash.Recover
+}
+
+// record 记录最近的消息，循环覆盖
+func (r *Room) record(msg *packet) {
+	if nil == r.crash || r.crash.Messages <= 0 {
+		return
+	}
+
+	rec := MessageRecord{
+		TimeMs:    time.Now().UnixNano() / int64(time.Millisecond),
+		ID:        msg.id,
+		Spectator: msg.spectator,
+	}
+	if p, ok := msg.msg.(*pb_packet.Packet); ok {
+		rec.MsgID = p.GetMessageID()
+		rec.Data = p.GetData()
+	}
+
+	if len(r.recent) < r.crash.Messages {
+		r.recent = append(r.recent, rec)
+		return
+	}
+	r.recent[r.recentPos] = rec
+	r.recentPos = (r.recentPos + 1) % len(r.recent)
+}
+
+// recentMessages 按时间顺序返回最近的消息
+func (r *Room) recentMessages() []MessageRecord {
+	ret := make([]MessageRecord, 0, len(r.recent))
+	ret = append(ret, r.recent[r.recentPos:]...)
+	return append(ret, r.recent[:r.recentPos]...)
+}
+
+// onCrash recover之后调用：写现场，通知客户端，上报失败的结果
+func (r *Room) onCrash(err interface{}, stack []byte) {
+	atomic.StoreInt32(&r.crashFlag, 1)
+	reported := atomic.SwapInt32(&r.closeFlag, 1) != 0
+
+	reason := fmt.Sprint(err)
+	l4g.Critical("[room(%d)] crashed:[%s]\n%s", r.roomID, reason, stack)
+
+	if len(r.crash.DumpDir) > 0 {
+		dump := &CrashDump{
+			RoomID:    r.roomID,
+			TypeID:    r.typeID,
+			TimeStamp: r.timeStamp,
+			CrashTime: time.Now().Unix(),
+			Error:     reason,
+			Stack:     string(stack),
+			Messages:  r.recentMessages(),
+		}
+		r.safeCall("dump game", func() {
+			dump.Game = r.game.DebugState(r.crash.Frames)
+		})
+		if name, err := dump.write(r.crash.DumpDir); nil != err {
+			l4g.Error("[room(%d)] write crash dump error:[%s]", r.roomID, err.Error())
+		} else {
+			l4g.Warn("[room(%d)] crash dump [%s]", r.roomID, name)
+		}
+	}
+
+	r.safeCall("close game", func() {
+		r.game.Close(pb.CLOSE_CLOSE_Crash)
+	})
+
+	// 已经正常结束上报过了
+	if reported {
+		return
+	}
+
+	outcome := &game.Outcome{}
+	r.safeCall("outcome", func() {
+		outcome = r.game.Outcome()
+	})
+	outcome.Status = game.OutcomeFailed
+	outcome.Error = reason
+	r.outcome = outcome
+
+	report := &Report{
+		RoomID:    r.roomID,
+		TypeID:    r.typeID,
+		TimeStamp: r.timeStamp,
+		EndTime:   time.Now().Unix(),
+		Outcome:   outcome,
+	}
+	r.safeCall("report", func() {
+		report = r.newReport()
+	})
+	r.report(report)
+}
+
+// safeCall 出错之后游戏状态可能已经坏了，访问游戏的地方都要再recover一次
+func (r *Room) safeCall(name string, f func()) {
+	defer func() {
+		if err := recover(); nil != err {
+			l4g.Error("[room(%d)] %s after crash error:[%v]", r.roomID, name, err)
+		}
+	}()
+	f()
+}
+
+// cleanup 清理游戏
+func (r *Room) cleanup() {
+	if r.IsCrashed() {
+		r.safeCall("cleanup", r.game.Cleanup)
+		return
+	}
+	r.game.Cleanup()
+}
+
+func (d *CrashDump) write(dir string) (string, error) {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if nil != err {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); nil != err {
+		return "", err
+	}
+
+	name := filepath.Join(dir, fmt.Sprintf("crash_%d_%d.json", d.RoomID, d.CrashTime))
+	return name, ioutil.WriteFile(name, b, 0644)
+}
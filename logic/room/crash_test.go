@@ -0,0 +1,82 @@
+package room
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+)
+
+func Test_Crash(t *testing.T) {
+	dir := t.TempDir()
+	reports := make(chan *Report, 1)
+
+	r := NewRoom(1, 0, []uint64{1, 2}, 0, "test", game.DefaultConfig())
+	r.SetResultSink(ResultSinkFunc(func(rp *Report) error {
+		reports <- rp
+		return nil
+	}))
+	r.SetCrashConfig(&CrashConfig{Recover: true, DumpDir: dir, Messages: 2, Frames: 10})
+	r.Drive(func() {})
+
+	for _, id := range []uint64{97, 98, 99} {
+		r.msgQ <- &packet{id: id, msg: pb_packet.NewPacket(uint8(pb.ID_MSG_Heartbeat), nil)}
+	}
+	if !r.Step() {
+		t.Fatal("room should be running")
+	}
+
+	r.callQ <- func() { panic("boom") }
+
+	if !r.Step() {
+		t.Fatal("room should wait before quit")
+	}
+	if !r.IsCrashed() || !r.IsOver() {
+		t.Fatal("room should be crashed")
+	}
+
+	select {
+	case rp := <-reports:
+		if rp.Outcome.Status != game.OutcomeFailed || rp.Outcome.Error != "boom" {
+			t.Errorf("wrong outcome %+v", rp.Outcome)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no report")
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "crash_1_*.json"))
+	if len(files) != 1 {
+		t.Fatalf("should write one dump, got %v", files)
+	}
+	b, err := ioutil.ReadFile(files[0])
+	if nil != err {
+		t.Fatal(err)
+	}
+	dump := &CrashDump{}
+	if err := json.Unmarshal(b, dump); nil != err {
+		t.Fatal(err)
+	}
+	if dump.Error != "boom" || !strings.Contains(dump.Stack, "Test_Crash") || nil == dump.Game {
+		t.Errorf("wrong dump %s", b)
+	}
+	if len(dump.Messages) != 2 || dump.Messages[0].ID != 98 || dump.Messages[1].ID != 99 {
+		t.Errorf("should keep last 2 messages, got %+v", dump.Messages)
+	}
+
+	// 延迟到了之后退出
+	r.closeAt = time.Now()
+	if r.Step() {
+		t.Fatal("room should quit")
+	}
+	select {
+	case <-r.doneChan:
+	default:
+		t.Error("doneChan should be closed")
+	}
+}
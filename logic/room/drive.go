@@ -0,0 +1,116 @@
+package room
+
+import (
+	"runtime/debug"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/pb"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+const (
+	QuitDelay      = time.Second * 3 // 游戏结束之后等多久再清理
+	kMaxStepEvents = 256             // 每次Step最多处理多少个消息，剩下的下次再处理
+)
+
+// Drive 由外部调度器驱动房间，代替Run。wake在房间有消息要处理的时候调用(任意goroutine)，
+// 调度器收到之后要在worker里调用Step，并且保证同一个房间的Step不会并发执行
+func (r *Room) Drive(wake func()) {
+	r.wg.Add(1)
+	r.wake = wake
+
+	now := time.Now()
+	r.clock = newFixedStep(now, r.tickInterval)
+	if r.timeout > 0 {
+		r.deadline = now.Add(r.timeout)
+	}
+
+	l4g.Info("[room(%d)] running...", r.roomID)
+}
+
+// Step 处理积压的消息，到时间就tick，返回false表示房间已经退出
+func (r *Room) Step() (ok bool) {
+	if r.recoverable() {
+		defer func() {
+			if err := recover(); nil != err {
+				r.onCrash(err, debug.Stack())
+				// 等关闭消息发出去再退出
+				r.closeAt = time.Now().Add(QuitDelay)
+				ok = true
+			}
+		}()
+	}
+
+	select {
+	case <-r.exitChan:
+		l4g.Error("[room(%d)] force exit", r.roomID)
+		r.quit()
+		return false
+	default:
+	}
+
+	now := time.Now()
+
+	// 已经结束，等一会再清理
+	if !r.closeAt.IsZero() {
+		if now.Before(r.closeAt) {
+			return true
+		}
+		r.quit()
+		return false
+	}
+
+	if r.timeout > 0 && !r.timeoutPaused && !now.Before(r.deadline) {
+		l4g.Error("[room(%d)] time out", r.roomID)
+		r.game.Timeout()
+		r.closeLater(now, pb.CLOSE_CLOSE_Timeout)
+		return true
+	}
+
+	for i := 0; i < kMaxStepEvents; i++ {
+		select {
+		case msg := <-r.msgQ:
+			r.handleMsg(msg)
+		case f := <-r.callQ:
+			f()
+		case c := <-r.inChan:
+			r.handleIn(c)
+		case c := <-r.outChan:
+			r.handleOut(c)
+		default:
+			i = kMaxStepEvents
+		}
+	}
+
+	if !r.tick(now) {
+		r.closeLater(now, pb.CLOSE_CLOSE_Over)
+		return true
+	}
+
+	// 没处理完的下次接着处理
+	if len(r.msgQ)+len(r.callQ)+len(r.inChan)+len(r.outChan) > 0 {
+		r.notify()
+	}
+
+	return true
+}
+
+func (r *Room) closeLater(now time.Time, reason pb.CLOSE) {
+	r.game.Close(reason)
+	r.closeAt = now.Add(QuitDelay)
+}
+
+func (r *Room) quit() {
+	r.cleanup()
+	l4g.Warn("[room(%d)] quit! total time=[%d]", r.roomID, time.Now().Unix()-r.timeStamp)
+	close(r.doneChan)
+	r.wg.Done()
+}
+
+// notify 通知调度器有消息要处理，Run驱动时什么都不做
+func (r *Room) notify() {
+	if nil != r.wake {
+		r.wake()
+	}
+}
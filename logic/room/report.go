@@ -0,0 +1,106 @@
+package room
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+)
+
+// Report 房间结束时的结算报告
+type Report struct {
+	RoomID     uint64
+	TypeID     int32
+	TimeStamp  int64 // 房间创建时间
+	EndTime    int64 // 结束时间
+	FrameCount uint32
+	Players    []game.PlayerStats
+	Outcome    *game.Outcome
+	Chat       []game.ChatRecord `json:",omitempty"`
+}
+
+// ResultSink 结算报告的去处，在房间之外的goroutine里调用
+type ResultSink interface {
+	Report(*Report) error
+}
+
+// ResultSinkFunc 函数形式的ResultSink
+type ResultSinkFunc func(*Report) error
+
+// Report 实现ResultSink
+func (f ResultSinkFunc) Report(r *Report) error {
+	return f(r)
+}
+
+// MultiSink 依次交给多个ResultSink
+type MultiSink []ResultSink
+
+// Report 实现ResultSink，返回第一个错误
+func (s MultiSink) Report(r *Report) error {
+	var ret error
+	for _, v := range s {
+		if err := v.Report(r); nil != err && nil == ret {
+			ret = err
+		}
+	}
+	return ret
+}
+
+// FileSink 每个房间的结算写成Dir下的一个json文件
+type FileSink struct {
+	Dir string
+}
+
+// Report 实现ResultSink
+func (s *FileSink) Report(r *Report) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if nil != err {
+		return err
+	}
+
+	if err := os.MkdirAll(s.Dir, 0755); nil != err {
+		return err
+	}
+
+	name := filepath.Join(s.Dir, fmt.Sprintf("%d_%d.json", r.RoomID, r.TimeStamp))
+	return ioutil.WriteFile(name, b, 0644)
+}
+
+// WebhookSink 把结算用json POST到URL
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookSink 构造
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		URL:    url,
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Report 实现ResultSink
+func (s *WebhookSink) Report(r *Report) error {
+	b, err := json.Marshal(r)
+	if nil != err {
+		return err
+	}
+
+	resp, err := s.Client.Post(s.URL, "application/json", bytes.NewReader(b))
+	if nil != err {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook status[%s]", resp.Status)
+	}
+	return nil
+}
@@ -1,11 +1,16 @@
 package room
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/byebyebruce/lockstepserver/logic/game"
+	"github.com/byebyebruce/lockstepserver/pb"
 	"github.com/byebyebruce/lockstepserver/pkg/network"
 	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
 
@@ -13,14 +18,32 @@ import (
 )
 
 const (
-	Frequency   = 30                      // 每分钟心跳频率
-	TickTimer   = time.Second / Frequency // 心跳Timer
-	TimeoutTime = time.Minute * 5         // 超时时间
+	TimeoutTime = time.Minute * 5 // 超时时间(游戏没有配置最长时间时)
+	kTimeoutGap = time.Minute     // 游戏有最长时间时，房间超时再多留一点时间
+	CallTimeout = time.Second     // 外部调用放进房间队列的超时时间
 )
 
+// SpectatorID 观战者连接的身份标识
+type SpectatorID uint64
+
 type packet struct {
-	id  uint64
-	msg network.Packet
+	id        uint64
+	spectator bool
+	conn      *network.Conn
+	msg       network.Packet
+}
+
+// Info 房间信息
+type Info struct {
+	ID         uint64
+	TypeID     int32
+	TimeStamp  int64
+	State      game.GameState
+	FrameCount uint32
+	Players    []game.PlayerStats
+	Spectators int
+	Outcome    *game.Outcome `json:",omitempty"`
+	Tick       TickStats
 }
 
 // Room 战斗房间
@@ -29,40 +52,96 @@ type Room struct {
 
 	roomID      uint64
 	players     []uint64
+	playersRW   sync.RWMutex
 	typeID      int32
 	closeFlag   int32
 	timeStamp   int64
 	secretKey   string
+	spectToken  string
 	logicServer string
 
 	exitChan chan struct{}
+	doneChan chan struct{}
+	callQ    chan func()
 	msgQ     chan *packet
 	inChan   chan *network.Conn
 	outChan  chan *network.Conn
 
-	game *game.Game
+	game         *game.Game
+	tickInterval time.Duration
+	timeout      time.Duration // 0表示不超时
+	clock        *fixedStep
+	outcome      *game.Outcome
+	sink         ResultSink
+
+	crash     *CrashConfig
+	crashFlag int32
+	recent    []MessageRecord // 最近的消息，崩溃时写到现场里
+	recentPos int
+
+	wake    func()    // 调度器驱动时有消息要处理就调用
+	closeAt time.Time // 调度器驱动时房间结束之后真正退出的时间
+
+	timeoutTimer  *time.Timer
+	deadline      time.Time     // 超时时间点
+	timeoutRemain time.Duration // 暂停时剩余的超时时间，暂停时间不算超时
+	timeoutPaused bool
 }
 
 // NewRoom 构造
-func NewRoom(id uint64, typeID int32, players []uint64, randomSeed int32, logicServer string) *Room {
+func NewRoom(id uint64, typeID int32, players []uint64, randomSeed int32, logicServer string, cfg *game.Config) *Room {
+	if nil == cfg {
+		cfg = game.DefaultConfig()
+	}
+
+	r := newRoom(id, typeID, players, logicServer, cfg)
+	r.game = game.NewGame(id, players, randomSeed, cfg, r)
+
+	return r
+}
+
+func newRoom(id uint64, typeID int32, players []uint64, logicServer string, cfg *game.Config) *Room {
 	r := &Room{
-		roomID:      id,
-		players:     players,
-		typeID:      typeID,
-		exitChan:    make(chan struct{}),
-		msgQ:        make(chan *packet, 2048),
-		outChan:     make(chan *network.Conn, 8),
-		inChan:      make(chan *network.Conn, 8),
-		timeStamp:   time.Now().Unix(),
-		logicServer: logicServer,
-		secretKey:   "test_room",
+		roomID:       id,
+		players:      append([]uint64(nil), players...),
+		typeID:       typeID,
+		exitChan:     make(chan struct{}),
+		doneChan:     make(chan struct{}),
+		callQ:        make(chan func(), 8),
+		msgQ:         make(chan *packet, 2048),
+		outChan:      make(chan *network.Conn, 8),
+		inChan:       make(chan *network.Conn, 8),
+		timeStamp:    time.Now().Unix(),
+		logicServer:  logicServer,
+		secretKey:    "test_room",
+		spectToken:   newToken(),
+		tickInterval: cfg.TickInterval(),
+		timeout:      TimeoutTime,
 	}
 
-	r.game = game.NewGame(id, players, randomSeed, r)
+	switch {
+	case cfg.RoomTimeout > 0:
+		r.timeout = cfg.RoomTimeout
+	case game.LockstepTurn == cfg.LockstepMode:
+		// 回合制一局多长和时间无关，只按MaxTurns限制
+		r.timeout = 0
+	case cfg.MaxDuration > 0:
+		r.timeout = cfg.ReadyTimeout + cfg.StartCountdown + cfg.MaxDuration + kTimeoutGap
+	}
 
 	return r
 }
 
+// SetResultSink 设置结算报告的去处，要在Run之前调用
+func (r *Room) SetResultSink(sink ResultSink) {
+	r.sink = sink
+}
+
+// TickInterval 每帧的时间
+func (r *Room) TickInterval() time.Duration {
+	return r.tickInterval
+}
+
 // ID room ID
 func (r *Room) ID() uint64 {
 	return r.roomID
@@ -73,6 +152,11 @@ func (r *Room) SecretKey() string {
 	return r.secretKey
 }
 
+// SpectatorToken 观战令牌
+func (r *Room) SpectatorToken() string {
+	return r.spectToken
+}
+
 // TimeStamp time stamp
 func (r *Room) TimeStamp() int64 {
 	return r.timeStamp
@@ -85,6 +169,9 @@ func (r *Room) IsOver() bool {
 
 // HasPlayer 是否有这个player
 func (r *Room) HasPlayer(id uint64) bool {
+	r.playersRW.RLock()
+	defer r.playersRW.RUnlock()
+
 	for _, v := range r.players {
 		if v == id {
 			return true
@@ -94,6 +181,123 @@ func (r *Room) HasPlayer(id uint64) bool {
 	return false
 }
 
+// AddPlayer 给房间增加一个玩家，返回座位号
+func (r *Room) AddPlayer(id uint64) (int32, error) {
+	var (
+		seat int32
+		err  error
+	)
+	if !r.call(func() {
+		seat, err = r.game.AddPlayer(id)
+	}) {
+		return 0, fmt.Errorf("room[%d] is closed", r.roomID)
+	}
+	if nil != err {
+		return 0, err
+	}
+
+	r.playersRW.Lock()
+	r.players = append(r.players, id)
+	r.playersRW.Unlock()
+
+	return seat, nil
+}
+
+// RemovePlayer 从房间删除一个玩家
+func (r *Room) RemovePlayer(id uint64) error {
+	var err error
+	if !r.call(func() {
+		err = r.game.RemovePlayer(id)
+	}) {
+		return fmt.Errorf("room[%d] is closed", r.roomID)
+	}
+	if nil != err {
+		return err
+	}
+
+	r.playersRW.Lock()
+	for k, v := range r.players {
+		if v == id {
+			r.players = append(r.players[:k], r.players[k+1:]...)
+			break
+		}
+	}
+	r.playersRW.Unlock()
+
+	return nil
+}
+
+// Kick 管理员踢人，cooldown为0表示不能再进入
+func (r *Room) Kick(id uint64, reason string, cooldown time.Duration) error {
+	var err error
+	if !r.call(func() {
+		err = r.game.Kick(id, 0, reason, cooldown)
+	}) {
+		return fmt.Errorf("room[%d] is closed", r.roomID)
+	}
+	return err
+}
+
+// SetTeam 设置玩家的队伍
+func (r *Room) SetTeam(id uint64, team int32) error {
+	var err error
+	if !r.call(func() {
+		err = r.game.SetTeam(id, team)
+	}) {
+		return fmt.Errorf("room[%d] is closed", r.roomID)
+	}
+	return err
+}
+
+// Info 获得房间信息，房间已经关闭返回false
+func (r *Room) Info() (*Info, bool) {
+	var info *Info
+	ok := r.call(func() {
+		info = &Info{
+			ID:         r.roomID,
+			TypeID:     r.typeID,
+			TimeStamp:  r.timeStamp,
+			State:      r.game.State,
+			FrameCount: r.game.FrameCount(),
+			Players:    r.game.PlayerStats(),
+			Spectators: r.game.SpectatorCount(),
+			Outcome:    r.outcome,
+			Tick:       r.clock.stats,
+		}
+	})
+	return info, ok
+}
+
+// call 在房间的goroutine里执行f并等它执行完，房间已经关闭或者放不进队列返回false
+func (r *Room) call(f func()) bool {
+	done := make(chan struct{})
+	timeout := time.NewTimer(CallTimeout)
+	defer timeout.Stop()
+
+	// 只有放进队列有超时，放进去之后一定会执行或者房间退出，不能提前返回，不然f会在调用者返回之后才执行
+	select {
+	case r.callQ <- func() { f(); close(done) }:
+		r.notify()
+	case <-r.doneChan:
+		return false
+	case <-timeout.C:
+		return false
+	}
+
+	select {
+	case <-done:
+		return true
+	case <-r.doneChan:
+		// 房间退出前可能刚好执行完
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
 func (r *Room) OnJoinGame(id, pid uint64) {
 	l4g.Warn("[room(%d)] onJoinGame %d", id, pid)
 }
@@ -104,19 +308,89 @@ func (r *Room) OnGameStart(id uint64) {
 func (r *Room) OnLeaveGame(id, pid uint64) {
 	l4g.Warn("[room(%d)] onLeaveGame %d", id, pid)
 }
+func (r *Room) OnNetworkChanged(id, pid uint64, degraded bool) {
+	l4g.Warn("[room(%d)] onNetworkChanged %d degraded=%v", id, pid, degraded)
+}
+
+// OnGamePause 暂停时停掉超时计时
+func (r *Room) OnGamePause(id uint64, paused bool) {
+	l4g.Warn("[room(%d)] onGamePause paused=%v", id, paused)
+	if 0 == r.timeout {
+		return
+	}
+
+	// 调度器驱动时没有timer，只看deadline
+	if paused {
+		if !r.timeoutPaused && (nil == r.timeoutTimer || r.timeoutTimer.Stop()) {
+			r.timeoutRemain = time.Until(r.deadline)
+			r.timeoutPaused = true
+		}
+		return
+	}
+
+	if r.timeoutPaused {
+		r.timeoutPaused = false
+		r.deadline = time.Now().Add(r.timeoutRemain)
+		if nil != r.timeoutTimer {
+			r.timeoutTimer.Reset(r.timeoutRemain)
+		}
+	}
+}
+
+// Pause 管理员暂停
+func (r *Room) Pause() bool {
+	ret := false
+	r.call(func() {
+		ret = r.game.Pause(0)
+	})
+	return ret
+}
+
+// Resume 管理员恢复
+func (r *Room) Resume() bool {
+	ret := false
+	r.call(func() {
+		ret = r.game.Resume(0)
+	})
+	return ret
+}
+
 func (r *Room) OnGameOver(id uint64) {
 	atomic.StoreInt32(&r.closeFlag, 1)
 
-	l4g.Warn("[room(%d)] onGameOver", id)
+	r.outcome = r.game.Outcome()
+	l4g.Warn("[room(%d)] onGameOver status=[%s] winner=[%d] dissenters=%v", id, r.outcome.Status, r.outcome.WinnerID, r.outcome.Dissenters)
+
+	r.report(r.newReport())
+}
+
+func (r *Room) newReport() *Report {
+	return &Report{
+		RoomID:     r.roomID,
+		TypeID:     r.typeID,
+		TimeStamp:  r.timeStamp,
+		EndTime:    time.Now().Unix(),
+		FrameCount: r.game.FrameCount(),
+		Players:    r.game.PlayerStats(),
+		Outcome:    r.outcome,
+		Chat:       r.game.ChatLog(),
+	}
+}
+
+// report 在单独的goroutine里交给sink
+func (r *Room) report(report *Report) {
+	if nil == r.sink {
+		return
+	}
 
 	r.wg.Add(1)
 
 	go func() {
 		defer r.wg.Done()
-		// TODO
-		// http result
+		if err := r.sink.Report(report); nil != err {
+			l4g.Error("[room(%d)] report result error:[%s]", r.roomID, err.Error())
+		}
 	}()
-
 }
 
 // OnConnect network.Conn callback
@@ -124,25 +398,37 @@ func (r *Room) OnConnect(conn *network.Conn) bool {
 
 	conn.SetCallback(r) // SetCallback只能在OnConnect里调
 	r.inChan <- conn
-	l4g.Warn("[room(%d)] OnConnect %d", r.roomID, conn.GetExtraData().(uint64))
+	r.notify()
+	l4g.Warn("[room(%d)] OnConnect %v", r.roomID, conn.GetExtraData())
 
 	return true
 }
 
+// OnSpectatorConnect 观战者连进来
+func (r *Room) OnSpectatorConnect(conn *network.Conn, id uint64) bool {
+	conn.PutExtraData(SpectatorID(id))
+	return r.OnConnect(conn)
+}
+
 // OnMessage network.Conn callback
 func (r *Room) OnMessage(conn *network.Conn, msg network.Packet) bool {
 
-	id, ok := conn.GetExtraData().(uint64)
-	if !ok {
+	p := &packet{
+		conn: conn,
+		msg:  msg,
+	}
+	switch id := conn.GetExtraData().(type) {
+	case uint64:
+		p.id = id
+	case SpectatorID:
+		p.id = uint64(id)
+		p.spectator = true
+	default:
 		l4g.Error("[room] OnMessage error conn don't have id")
 		return false
 	}
-
-	p := &packet{
-		id:  id,
-		msg: msg,
-	}
 	r.msgQ <- p
+	r.notify()
 
 	return true
 }
@@ -150,11 +436,8 @@ func (r *Room) OnMessage(conn *network.Conn, msg network.Packet) bool {
 // OnClose network.Conn callback
 func (r *Room) OnClose(conn *network.Conn) {
 	r.outChan <- conn
-	if id, ok := conn.GetExtraData().(uint64); ok {
-		l4g.Warn("[room(%d)] OnClose %d", r.roomID, id)
-	} else {
-		l4g.Warn("[room(%d)] OnClose no id", r.roomID)
-	}
+	r.notify()
+	l4g.Warn("[room(%d)] OnClose %v", r.roomID, conn.GetExtraData())
 
 }
 
@@ -162,66 +445,63 @@ func (r *Room) OnClose(conn *network.Conn) {
 func (r *Room) Run() {
 	r.wg.Add(1)
 	defer r.wg.Done()
+	defer close(r.doneChan)
 	defer func() {
-		/*
-			err := recover()
-			if nil != err {
-				l4g.Error("[room(%d)] Run error:%+v", r.roomID, err)
-			}*/
-		r.game.Cleanup()
+		if r.recoverable() {
+			if err := recover(); nil != err {
+				r.onCrash(err, debug.Stack())
+				// 等关闭消息发出去
+				<-time.After(QuitDelay)
+			}
+		}
+		r.cleanup()
 		l4g.Warn("[room(%d)] quit! total time=[%d]", r.roomID, time.Now().Unix()-r.timeStamp)
 	}()
 
-	// 心跳
-	tickerTick := time.NewTicker(TickTimer)
+	// 心跳，按开始时间补上落后的tick
+	tickerTick := time.NewTicker(r.tickInterval)
 	defer tickerTick.Stop()
-
-	// 超时timer
-	timeoutTimer := time.NewTimer(TimeoutTime)
+	r.clock = newFixedStep(time.Now(), r.tickInterval)
+
+	// 超时timer，不限制时间的房间没有
+	var timeoutC <-chan time.Time
+	if r.timeout > 0 {
+		r.deadline = time.Now().Add(r.timeout)
+		r.timeoutTimer = time.NewTimer(r.timeout)
+		defer r.timeoutTimer.Stop()
+		timeoutC = r.timeoutTimer.C
+	}
 
 	l4g.Info("[room(%d)] running...", r.roomID)
 
+	reason := pb.CLOSE_CLOSE_Over
 LOOP:
 	for {
 		select {
 		case <-r.exitChan:
 			l4g.Error("[room(%d)] force exit", r.roomID)
 			return
-		case <-timeoutTimer.C:
+		case <-timeoutC:
 			l4g.Error("[room(%d)] time out", r.roomID)
+			r.game.Timeout()
+			reason = pb.CLOSE_CLOSE_Timeout
 			break LOOP
 		case msg := <-r.msgQ:
-			r.game.ProcessMsg(msg.id, msg.msg.(*pb_packet.Packet))
+			r.handleMsg(msg)
+		case f := <-r.callQ:
+			f()
 		case <-tickerTick.C:
-			if !r.game.Tick(time.Now().Unix()) {
-				l4g.Info("[room(%d)] tick over", r.roomID)
+			if !r.tick(time.Now()) {
 				break LOOP
 			}
 		case c := <-r.inChan:
-			id, ok := c.GetExtraData().(uint64)
-			if ok {
-				if r.game.JoinGame(id, c) {
-					l4g.Info("[room(%d)] player[%d] join room ok", r.roomID, id)
-				} else {
-					l4g.Error("[room(%d)] player[%d] join room failed", r.roomID, id)
-					c.Close()
-				}
-			} else {
-				c.Close()
-				l4g.Error("[room(%d)] inChan don't have id", r.roomID)
-			}
-
+			r.handleIn(c)
 		case c := <-r.outChan:
-			if id, ok := c.GetExtraData().(uint64); ok {
-				r.game.LeaveGame(id)
-			} else {
-				c.Close()
-				l4g.Error("[room(%d)] outChan don't have id", r.roomID)
-			}
+			r.handleOut(c)
 		}
 	}
 
-	r.game.Close()
+	r.game.Close(reason)
 
 	for i := 3; i > 0; i-- {
 		<-time.After(time.Second)
@@ -229,8 +509,71 @@ LOOP:
 	}
 }
 
+func (r *Room) handleMsg(msg *packet) {
+	r.record(msg)
+	if msg.spectator {
+		r.game.ProcessSpectatorMsg(msg.id, msg.msg.(*pb_packet.Packet))
+	} else {
+		r.game.ProcessMsg(msg.id, msg.conn, msg.msg.(*pb_packet.Packet))
+	}
+}
+
+// tick 按固定步长补上落后的tick，返回false表示游戏结束
+func (r *Room) tick(now time.Time) bool {
+	for n := r.clock.advance(now); n > 0; n-- {
+		if !r.game.Tick(now) {
+			l4g.Info("[room(%d)] tick over", r.roomID)
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Room) handleIn(c *network.Conn) {
+	switch id := c.GetExtraData().(type) {
+	case uint64:
+		if r.game.JoinGame(id, c) {
+			l4g.Info("[room(%d)] player[%d] join room ok", r.roomID, id)
+		} else {
+			l4g.Error("[room(%d)] player[%d] join room failed", r.roomID, id)
+			game.CloseLater(c)
+		}
+	case SpectatorID:
+		if r.game.JoinSpectator(uint64(id), c) {
+			l4g.Info("[room(%d)] spectator[%d] join room ok", r.roomID, id)
+		} else {
+			l4g.Error("[room(%d)] spectator[%d] join room failed", r.roomID, id)
+			game.CloseLater(c)
+		}
+	default:
+		c.Close()
+		l4g.Error("[room(%d)] inChan don't have id", r.roomID)
+	}
+}
+
+func (r *Room) handleOut(c *network.Conn) {
+	switch id := c.GetExtraData().(type) {
+	case uint64:
+		r.game.LeaveGame(id)
+	case SpectatorID:
+		r.game.LeaveSpectator(uint64(id))
+	case nil:
+		// 已经和玩家解绑的连接(加入被拒绝、被踢、被顶掉)
+	default:
+		c.Close()
+		l4g.Error("[room(%d)] outChan don't have id", r.roomID)
+	}
+}
+
 // Stop 强制关闭
 func (r *Room) Stop() {
 	close(r.exitChan)
+	r.notify()
 	r.wg.Wait()
 }
+
+func newToken() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
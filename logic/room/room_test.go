@@ -0,0 +1,97 @@
+package room
+
+import (
+	"testing"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+)
+
+func Test_CallWaitsAfterEnqueue(t *testing.T) {
+	r := NewRoom(1, 0, []uint64{1, 2}, 0, "test", game.DefaultConfig())
+	r.Drive(func() {})
+
+	ran := false
+	ret := make(chan bool)
+	go func() {
+		ret <- r.call(func() { ran = true })
+	}()
+
+	// 房间很忙，放进队列之后超过CallTimeout才处理
+	time.Sleep(CallTimeout + time.Millisecond*100)
+	select {
+	case <-ret:
+		t.Fatal("call should wait for the queued closure")
+	default:
+	}
+
+	r.Step()
+	if !<-ret || !ran {
+		t.Error("call should return true after the closure runs")
+	}
+}
+
+func Test_Timeout(t *testing.T) {
+	reports := make(chan *Report, 1)
+
+	r := NewRoom(1, 0, []uint64{1, 2}, 0, "test", game.DefaultConfig())
+	r.SetResultSink(ResultSinkFunc(func(rp *Report) error {
+		reports <- rp
+		return nil
+	}))
+	r.Drive(func() {})
+
+	// 房间超时也要出结算
+	r.deadline = time.Now()
+	if !r.Step() {
+		t.Fatal("room should wait before quit")
+	}
+	if !r.IsOver() {
+		t.Fatal("timeout room should be over")
+	}
+	select {
+	case rp := <-reports:
+		if rp.Outcome.Status != game.OutcomeTimeout {
+			t.Errorf("wrong outcome %+v", rp.Outcome)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no report")
+	}
+	if nil == r.outcome || r.outcome.Status != game.OutcomeTimeout {
+		t.Errorf("room outcome should be timeout %+v", r.outcome)
+	}
+
+	r.closeAt = time.Now()
+	if r.Step() {
+		t.Fatal("room should quit")
+	}
+}
+
+func Test_TurnTimeout(t *testing.T) {
+	cfg := game.DefaultConfig()
+	cfg.LockstepMode = game.LockstepTurn
+	cfg.MaxDuration = time.Minute
+	cfg.MaxTurns = 50
+
+	// 回合制不按墙钟超时，回合数不按帧率算
+	r := NewRoom(1, 0, []uint64{1, 2}, 0, "test", cfg)
+	if r.timeout != 0 || cfg.MaxFrames() != 50 {
+		t.Fatalf("turn room should not time out %v %d", r.timeout, cfg.MaxFrames())
+	}
+	r.Drive(func() {})
+	r.deadline = time.Now()
+	if !r.Step() || r.IsOver() {
+		t.Fatal("turn room should keep running")
+	}
+	r.OnGamePause(1, true)
+	r.OnGamePause(1, false)
+	if !r.Step() || r.IsOver() {
+		t.Fatal("turn room should keep running after pause")
+	}
+
+	// 单独配置了房间超时的按配置
+	cfg.RoomTimeout = time.Second
+	if r := NewRoom(2, 0, []uint64{1, 2}, 0, "test", cfg); r.timeout != time.Second {
+		t.Errorf("room timeout should be configurable %v", r.timeout)
+	}
+}
@@ -0,0 +1,125 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+)
+
+// Duration 配置文件里写成"20s"、"5m"这样的字符串
+type Duration time.Duration
+
+// MarshalJSON 输出成字符串
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON 从字符串读取
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); nil != err {
+		return fmt.Errorf("duration should be a string like \"20s\": %s", b)
+	}
+	v, err := time.ParseDuration(s)
+	if nil != err {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// RoomType 一种房间(玩法)的设置，CreateRoom的typeID对应这里的ID。为0或者为空的设置用管理器的默认配置
+type RoomType struct {
+	ID   int32
+	Name string
+
+	TickRate     uint32   // 每秒多少帧
+	MaxPlayers   uint32   // 最多多少个座位
+	ReadyTimeout Duration // 准备阶段最长时间
+	MaxDuration  Duration // 游戏最长时间(不算暂停)
+
+	InputDelay         uint32   // 服务端输入延迟(帧)
+	MaxInputAhead      uint32   // 输入的目标帧最多可以超前多少帧
+	InputRate          uint32   // 每个玩家每秒最多多少个输入
+	MaxInputViolations uint32   // 输入违规超过这么多次踢掉
+	Validators         []string // 输入校验，RegisterNamedValidator注册的名字，按顺序执行
+
+	ResultPolicy *game.ResultPolicy `json:",omitempty"` // 结果共识方式(unanimous/majority/quorum)
+	ResultQuorum uint32             // quorum方式下至少多少个提交一致
+}
+
+// LoadRoomTypes 从json文件读取房间类型列表
+func LoadRoomTypes(file string) ([]*RoomType, error) {
+	b, err := ioutil.ReadFile(file)
+	if nil != err {
+		return nil, err
+	}
+
+	var types []*RoomType
+	if err := json.Unmarshal(b, &types); nil != err {
+		return nil, fmt.Errorf("parse room types[%s] error: %s", file, err.Error())
+	}
+
+	return types, nil
+}
+
+// config 在base的基础上套用这个类型的设置
+func (t *RoomType) config(base *game.Config, validators map[string]func(id uint64) game.InputValidator) (*game.Config, error) {
+	c := *base
+
+	if t.TickRate > 0 {
+		c.TickRate = t.TickRate
+	}
+	if t.MaxPlayers > 0 {
+		c.MaxPlayers = t.MaxPlayers
+	}
+	if t.ReadyTimeout > 0 {
+		c.ReadyTimeout = time.Duration(t.ReadyTimeout)
+	}
+	if t.MaxDuration > 0 {
+		c.MaxDuration = time.Duration(t.MaxDuration)
+	}
+	if t.InputDelay > 0 {
+		c.InputDelay = t.InputDelay
+	}
+	if t.MaxInputAhead > 0 {
+		c.MaxInputAhead = t.MaxInputAhead
+	}
+	if t.MaxInputViolations > 0 {
+		c.MaxInputViolations = t.MaxInputViolations
+	}
+	if nil != t.ResultPolicy {
+		c.ResultPolicy = *t.ResultPolicy
+	}
+	if t.ResultQuorum > 0 {
+		c.ResultQuorum = t.ResultQuorum
+	}
+
+	news := make([]func(id uint64) game.InputValidator, 0, len(t.Validators))
+	for _, name := range t.Validators {
+		f, ok := validators[name]
+		if !ok {
+			return nil, fmt.Errorf("room type[%d] validator[%s] not registered", t.ID, name)
+		}
+		news = append(news, f)
+	}
+
+	inputRate := t.InputRate
+	if len(news) > 0 || inputRate > 0 {
+		c.NewInputValidator = func(id uint64) game.InputValidator {
+			chain := make(game.ChainValidator, 0, len(news)+1)
+			if inputRate > 0 {
+				chain = append(chain, game.NewRateValidator(inputRate))
+			}
+			for _, f := range news {
+				chain = append(chain, f(id))
+			}
+			return chain
+		}
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/game"
+)
+
+func Test_RoomType(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "types.json")
+	ioutil.WriteFile(file, []byte(`[
+		{"ID": 1, "TickRate": 15, "MaxPlayers": 4, "MaxDuration": "2m", "InputRate": 10, "Validators": ["range"], "ResultPolicy": "quorum", "ResultQuorum": 3}
+	]`), 0644)
+
+	m := NewRoomManager()
+	if err := m.LoadRoomTypes(file); nil == err {
+		t.Fatal("validator should be registered first")
+	}
+
+	m.RegisterNamedValidator("range", func(id uint64) game.InputValidator {
+		return &game.RangeValidator{MinX: -10, MaxX: 10, MinY: -10, MaxY: 10}
+	})
+	if err := m.LoadRoomTypes(file); nil != err {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	rt := m.RoomType(1)
+	if nil == rt || time.Duration(rt.MaxDuration) != time.Minute*2 || *rt.ResultPolicy != game.ResultQuorum {
+		t.Fatalf("wrong room type %+v", rt)
+	}
+
+	cfg, err := rt.config(game.DefaultConfig(), m.validators)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if cfg.TickInterval() != time.Second/15 || cfg.MaxPlayers != 4 || cfg.MaxFrames() != 15*120 || cfg.ResultQuorum != 3 {
+		t.Errorf("wrong config %+v", cfg)
+	}
+	if v, ok := cfg.NewInputValidator(1).(game.ChainValidator); !ok || len(v) != 2 {
+		t.Errorf("should chain rate and range validator, got %#v", cfg.NewInputValidator(1))
+	}
+
+	// 按类型ID注册的校验接在后面
+	m.RegisterInputValidator(1, func(id uint64) game.InputValidator {
+		return &game.SkillValidator{Allowed: func(playerID uint64, seat int32, sid int32) bool { return true }}
+	})
+	m.rw.RLock()
+	cfg, err = m.typeConfig(1)
+	m.rw.RUnlock()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if v, ok := cfg.NewInputValidator(1).(game.ChainValidator); !ok || len(v) != 2 {
+		t.Errorf("should chain type validator, got %#v", cfg.NewInputValidator(1))
+	} else if _, ok := v[1].(*game.SkillValidator); !ok {
+		t.Errorf("type validator should run last, got %#v", v)
+	}
+
+	if _, err := m.CreateRoom(1, 3, []uint64{1, 2}, 0, "test"); nil == err {
+		t.Error("unknown room type should be rejected")
+	}
+	if _, err := m.CreateRoom(3, 1, []uint64{1, 2, 3, 4, 5}, 0, "test"); nil == err {
+		t.Error("too many players should be rejected")
+	}
+	r, err := m.CreateRoom(2, 1, []uint64{1, 2}, 0, "test")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if r.TickInterval() != time.Second/15 {
+		t.Errorf("wrong tick interval %v", r.TickInterval())
+	}
+}
@@ -0,0 +1,121 @@
+package logic
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic/room"
+)
+
+const (
+	kWheelInterval  = time.Millisecond * 5 // 时间轮精度
+	kWheelSlots     = 256
+	kSchedQueueSize = 4096
+)
+
+// roomTask 调度器里的一个房间
+type roomTask struct {
+	room    *room.Room
+	state   int32 // 0空闲 1排队或者执行中
+	pending int32 // 执行中又有新消息
+	over    int32
+	done    func()
+}
+
+// Scheduler 用固定数量的worker和一个共享的时间轮驱动所有房间，代替每个房间一个goroutine。
+// 同一个房间同时只会在一个worker里执行
+type Scheduler struct {
+	wheel *timingWheel
+	queue chan *roomTask
+	wg    sync.WaitGroup
+}
+
+// NewScheduler 构造
+func NewScheduler(workers int) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+
+	s := &Scheduler{
+		wheel: newTimingWheel(kWheelInterval, kWheelSlots),
+		queue: make(chan *roomTask, kSchedQueueSize),
+	}
+
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	return s
+}
+
+// Add 开始驱动房间，房间退出之后在单独的goroutine里调用done
+func (s *Scheduler) Add(r *room.Room, done func()) {
+	t := &roomTask{
+		room: r,
+		done: done,
+	}
+
+	r.Drive(func() { s.wake(t) })
+
+	// 每个tick唤醒一次
+	var tick func()
+	tick = func() {
+		if 0 != atomic.LoadInt32(&t.over) {
+			return
+		}
+		s.wake(t)
+		s.wheel.add(r.TickInterval(), tick)
+	}
+	s.wheel.add(r.TickInterval(), tick)
+}
+
+// Stop 停止，要先停掉所有房间
+func (s *Scheduler) Stop() {
+	s.wheel.stop()
+	close(s.queue)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) wake(t *roomTask) {
+	atomic.StoreInt32(&t.pending, 1)
+	if atomic.CompareAndSwapInt32(&t.state, 0, 1) {
+		s.enqueue(t)
+	}
+}
+
+func (s *Scheduler) enqueue(t *roomTask) {
+	select {
+	case s.queue <- t:
+	default:
+		// 队列满了不能阻塞worker和时间轮
+		go func() { s.queue <- t }()
+	}
+}
+
+func (s *Scheduler) work() {
+	defer s.wg.Done()
+
+	for t := range s.queue {
+		s.step(t)
+	}
+}
+
+func (s *Scheduler) step(t *roomTask) {
+	atomic.StoreInt32(&t.pending, 0)
+
+	if !t.room.Step() {
+		// 退出之后state一直是1，不会再被调度
+		atomic.StoreInt32(&t.over, 1)
+		go t.done()
+		return
+	}
+
+	atomic.StoreInt32(&t.state, 0)
+
+	// 执行期间又来了消息，重新排队让其他房间先执行
+	if 0 != atomic.LoadInt32(&t.pending) && atomic.CompareAndSwapInt32(&t.state, 0, 1) {
+		s.enqueue(t)
+	}
+}
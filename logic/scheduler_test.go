@@ -0,0 +1,105 @@
+//go:build !windows
+// +build !windows
+
+package logic
+
+import (
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	l4g "github.com/alecthomas/log4go"
+)
+
+func Test_TimingWheel(t *testing.T) {
+	w := newTimingWheel(time.Millisecond, 8)
+	defer w.stop()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for _, d := range []time.Duration{time.Millisecond, time.Millisecond * 5, time.Millisecond * 8, time.Millisecond * 20} {
+		d := d
+		wg.Add(1)
+		w.add(d, func() {
+			defer wg.Done()
+			if e := time.Since(start); e < d {
+				t.Errorf("timer[%v] fired too early [%v]", d, e)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func Test_Scheduler(t *testing.T) {
+	m := NewRoomManager()
+	m.UseScheduler(2)
+
+	for i := uint64(1); i <= 10; i++ {
+		if _, err := m.CreateRoom(i, 0, []uint64{i*10 + 1, i*10 + 2}, 0, "test"); nil != err {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	r := m.GetRoom(1)
+	info, ok := r.Info()
+	if !ok || info.Tick.Ticks == 0 {
+		t.Fatalf("room should be ticking %+v", info)
+	}
+
+	m.Stop()
+	if m.RoomNum() != 0 {
+		t.Errorf("rooms should be removed, got %d", m.RoomNum())
+	}
+}
+
+// benchRooms 跑rooms个房间1秒，统计CPU时间和tick延迟
+func benchRooms(b *testing.B, rooms int, workers int) {
+	l4g.Close()
+	l4g.AddFilter("stdout", l4g.ERROR, l4g.NewConsoleLogWriter())
+
+	for n := 0; n < b.N; n++ {
+		m := NewRoomManager()
+		if workers > 0 {
+			m.UseScheduler(workers)
+		}
+
+		var before syscall.Rusage
+		syscall.Getrusage(syscall.RUSAGE_SELF, &before)
+
+		for i := 0; i < rooms; i++ {
+			m.CreateRoom(uint64(i+1), 0, []uint64{1, 2}, 0, "test")
+		}
+		time.Sleep(time.Second)
+
+		var lateness, maxLateness time.Duration
+		for i := 0; i < rooms; i++ {
+			if info, ok := m.GetRoom(uint64(i + 1)).Info(); ok {
+				lateness += info.Tick.Lateness
+				if info.Tick.MaxLateness > maxLateness {
+					maxLateness = info.Tick.MaxLateness
+				}
+			}
+		}
+
+		var after syscall.Rusage
+		syscall.Getrusage(syscall.RUSAGE_SELF, &after)
+		m.Stop()
+
+		cpu := time.Duration(after.Utime.Nano()+after.Stime.Nano()-before.Utime.Nano()-before.Stime.Nano()) * time.Nanosecond
+		b.ReportMetric(float64(cpu.Milliseconds()), "cpu-ms/s")
+		b.ReportMetric(float64((lateness / time.Duration(rooms)).Microseconds()), "late-us")
+		b.ReportMetric(float64(maxLateness.Microseconds()), "maxlate-us")
+	}
+}
+
+func BenchmarkRoomGoroutine(b *testing.B) {
+	benchRooms(b, 2000, 0)
+}
+
+func BenchmarkRoomScheduler(b *testing.B) {
+	benchRooms(b, 2000, runtime.NumCPU())
+}
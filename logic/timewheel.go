@@ -0,0 +1,100 @@
+package logic
+
+import (
+	"sync"
+	"time"
+)
+
+type wheelTimer struct {
+	rounds int
+	f      func()
+}
+
+// timingWheel 时间轮，所有定时器共用一个goroutine，回调要尽量轻
+type timingWheel struct {
+	interval time.Duration
+	slots    [][]*wheelTimer
+	pos      int
+	mu       sync.Mutex
+	exitChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newTimingWheel(interval time.Duration, slots int) *timingWheel {
+	w := &timingWheel{
+		interval: interval,
+		slots:    make([][]*wheelTimer, slots),
+		exitChan: make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w
+}
+
+// add d之后调用f(精度是interval)
+func (w *timingWheel) add(d time.Duration, f func()) {
+	ticks := int(d / w.interval)
+	if ticks < 1 {
+		ticks = 1
+	}
+
+	w.mu.Lock()
+	n := len(w.slots)
+	idx := (w.pos + ticks) % n
+	w.slots[idx] = append(w.slots[idx], &wheelTimer{
+		rounds: (ticks - 1) / n,
+		f:      f,
+	})
+	w.mu.Unlock()
+}
+
+func (w *timingWheel) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.exitChan:
+			return
+		case <-ticker.C:
+			for _, t := range w.advance() {
+				t.f()
+			}
+		}
+	}
+}
+
+// advance 前进一格，返回到期的定时器
+func (w *timingWheel) advance() []*wheelTimer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pos = (w.pos + 1) % len(w.slots)
+	slot := w.slots[w.pos]
+
+	var fired []*wheelTimer
+	remain := slot[:0]
+	for _, t := range slot {
+		if t.rounds > 0 {
+			t.rounds--
+			remain = append(remain, t)
+			continue
+		}
+		fired = append(fired, t)
+	}
+	for i := len(remain); i < len(slot); i++ {
+		slot[i] = nil
+	}
+	w.slots[w.pos] = remain
+
+	return fired
+}
+
+func (w *timingWheel) stop() {
+	close(w.exitChan)
+	w.wg.Wait()
+}
@@ -24,18 +24,30 @@ const (
 type ID int32
 
 const (
-	ID_MSG_BEGIN     ID = 0
-	ID_MSG_Connect   ID = 1   //连接(客户端发来第一个消息)
-	ID_MSG_Heartbeat ID = 2   //心跳(服务端返回Connect成功之后每隔1秒发送一个心跳包)
-	ID_MSG_JoinRoom  ID = 10  //进入
-	ID_MSG_Progress  ID = 20  //进度
-	ID_MSG_Ready     ID = 30  //准备
-	ID_MSG_Start     ID = 40  //开始
-	ID_MSG_Frame     ID = 50  //帧数据
-	ID_MSG_Input     ID = 60  //输入
-	ID_MSG_Result    ID = 70  //结果
-	ID_MSG_Close     ID = 100 //房间关闭
-	ID_MSG_END       ID = 255
+	ID_MSG_BEGIN       ID = 0
+	ID_MSG_Connect     ID = 1   //连接(客户端发来第一个消息)
+	ID_MSG_Heartbeat   ID = 2   //心跳(服务端返回Connect成功之后每隔1秒发送一个心跳包)
+	ID_MSG_Ping        ID = 3   //测延迟和对时(进入房间之后可以代替心跳)
+	ID_MSG_JoinRoom    ID = 10  //进入
+	ID_MSG_Progress    ID = 20  //进度
+	ID_MSG_Ready       ID = 30  //准备
+	ID_MSG_Start       ID = 40  //开始(房主开始模式下房主也可以发)
+	ID_MSG_Countdown   ID = 41  //准备阶段状态和开始倒计时
+	ID_MSG_Frame       ID = 50  //帧数据
+	ID_MSG_Input       ID = 60  //输入
+	ID_MSG_InputReject ID = 61  //输入被拒绝
+	ID_MSG_Hash        ID = 62  //状态hash校验(服务端有权威模拟时)
+	ID_MSG_Snapshot    ID = 63  //状态快照(分片上传，重连时下发)
+	ID_MSG_Result      ID = 70  //结果
+	ID_MSG_Surrender   ID = 71  //投降
+	ID_MSG_Leave       ID = 72  //主动离开
+	ID_MSG_Kick        ID = 73  //踢人(房主踢人模式下房主也可以发)
+	ID_MSG_NetState    ID = 80  //玩家网络状态变化
+	ID_MSG_Pause       ID = 90  //暂停/恢复
+	ID_MSG_Chat        ID = 110 //聊天和表情(不进帧)
+	ID_MSG_Relay       ID = 120 //客户端之间的自定义消息(不进帧)
+	ID_MSG_Close       ID = 100 //房间关闭
+	ID_MSG_END         ID = 255
 )
 
 // Enum value maps for ID.
@@ -44,29 +56,53 @@ var (
 		0:   "MSG_BEGIN",
 		1:   "MSG_Connect",
 		2:   "MSG_Heartbeat",
+		3:   "MSG_Ping",
 		10:  "MSG_JoinRoom",
 		20:  "MSG_Progress",
 		30:  "MSG_Ready",
 		40:  "MSG_Start",
+		41:  "MSG_Countdown",
 		50:  "MSG_Frame",
 		60:  "MSG_Input",
+		61:  "MSG_InputReject",
+		62:  "MSG_Hash",
+		63:  "MSG_Snapshot",
 		70:  "MSG_Result",
+		71:  "MSG_Surrender",
+		72:  "MSG_Leave",
+		73:  "MSG_Kick",
+		80:  "MSG_NetState",
+		90:  "MSG_Pause",
+		110: "MSG_Chat",
+		120: "MSG_Relay",
 		100: "MSG_Close",
 		255: "MSG_END",
 	}
 	ID_value = map[string]int32{
-		"MSG_BEGIN":     0,
-		"MSG_Connect":   1,
-		"MSG_Heartbeat": 2,
-		"MSG_JoinRoom":  10,
-		"MSG_Progress":  20,
-		"MSG_Ready":     30,
-		"MSG_Start":     40,
-		"MSG_Frame":     50,
-		"MSG_Input":     60,
-		"MSG_Result":    70,
-		"MSG_Close":     100,
-		"MSG_END":       255,
+		"MSG_BEGIN":       0,
+		"MSG_Connect":     1,
+		"MSG_Heartbeat":   2,
+		"MSG_Ping":        3,
+		"MSG_JoinRoom":    10,
+		"MSG_Progress":    20,
+		"MSG_Ready":       30,
+		"MSG_Start":       40,
+		"MSG_Countdown":   41,
+		"MSG_Frame":       50,
+		"MSG_Input":       60,
+		"MSG_InputReject": 61,
+		"MSG_Hash":        62,
+		"MSG_Snapshot":    63,
+		"MSG_Result":      70,
+		"MSG_Surrender":   71,
+		"MSG_Leave":       72,
+		"MSG_Kick":        73,
+		"MSG_NetState":    80,
+		"MSG_Pause":       90,
+		"MSG_Chat":        110,
+		"MSG_Relay":       120,
+		"MSG_Close":       100,
+		"MSG_END":         255,
 	}
 )
 
@@ -101,28 +137,52 @@ func (ID) EnumDescriptor() ([]byte, []int) {
 type ERRORCODE int32
 
 const (
-	ERRORCODE_ERR_Ok        ERRORCODE = 0 //OK
-	ERRORCODE_ERR_NoPlayer  ERRORCODE = 1 //没有这个玩家
-	ERRORCODE_ERR_NoRoom    ERRORCODE = 2 //没有房间
-	ERRORCODE_ERR_RoomState ERRORCODE = 3 //房间状态不正确
-	ERRORCODE_ERR_Token     ERRORCODE = 4 //Token验证失败
+	ERRORCODE_ERR_Ok            ERRORCODE = 0  //OK
+	ERRORCODE_ERR_NoPlayer      ERRORCODE = 1  //没有这个玩家
+	ERRORCODE_ERR_NoRoom        ERRORCODE = 2  //没有房间
+	ERRORCODE_ERR_RoomState     ERRORCODE = 3  //房间状态不正确
+	ERRORCODE_ERR_Token         ERRORCODE = 4  //Token验证失败
+	ERRORCODE_ERR_InputLate     ERRORCODE = 5  //输入的帧已经过去
+	ERRORCODE_ERR_InputAhead    ERRORCODE = 6  //输入的帧太超前
+	ERRORCODE_ERR_SpectatorFull ERRORCODE = 7  //观战人数已满
+	ERRORCODE_ERR_Left          ERRORCODE = 8  //已经主动离开
+	ERRORCODE_ERR_RateLimit     ERRORCODE = 9  //发送太频繁
+	ERRORCODE_ERR_TooLong       ERRORCODE = 10 //内容太长
+	ERRORCODE_ERR_Filtered      ERRORCODE = 11 //内容被过滤
+	ERRORCODE_ERR_Kicked        ERRORCODE = 12 //被踢出房间
 )
 
 // Enum value maps for ERRORCODE.
 var (
 	ERRORCODE_name = map[int32]string{
-		0: "ERR_Ok",
-		1: "ERR_NoPlayer",
-		2: "ERR_NoRoom",
-		3: "ERR_RoomState",
-		4: "ERR_Token",
+		0:  "ERR_Ok",
+		1:  "ERR_NoPlayer",
+		2:  "ERR_NoRoom",
+		3:  "ERR_RoomState",
+		4:  "ERR_Token",
+		5:  "ERR_InputLate",
+		6:  "ERR_InputAhead",
+		7:  "ERR_SpectatorFull",
+		8:  "ERR_Left",
+		9:  "ERR_RateLimit",
+		10: "ERR_TooLong",
+		11: "ERR_Filtered",
+		12: "ERR_Kicked",
 	}
 	ERRORCODE_value = map[string]int32{
-		"ERR_Ok":        0,
-		"ERR_NoPlayer":  1,
-		"ERR_NoRoom":    2,
-		"ERR_RoomState": 3,
-		"ERR_Token":     4,
+		"ERR_Ok":            0,
+		"ERR_NoPlayer":      1,
+		"ERR_NoRoom":        2,
+		"ERR_RoomState":     3,
+		"ERR_Token":         4,
+		"ERR_InputLate":     5,
+		"ERR_InputAhead":    6,
+		"ERR_SpectatorFull": 7,
+		"ERR_Left":          8,
+		"ERR_RateLimit":     9,
+		"ERR_TooLong":       10,
+		"ERR_Filtered":      11,
+		"ERR_Kicked":        12,
 	}
 )
 
@@ -153,15 +213,231 @@ func (ERRORCODE) EnumDescriptor() ([]byte, []int) {
 	return file_message_proto_rawDescGZIP(), []int{1}
 }
 
+//消息的接收范围
+type SCOPE int32
+
+const (
+	SCOPE_SCOPE_All    SCOPE = 0 //所有玩家
+	SCOPE_SCOPE_Team   SCOPE = 1 //同队伍的玩家
+	SCOPE_SCOPE_Others SCOPE = 2 //除了自己的所有玩家
+	SCOPE_SCOPE_Seat   SCOPE = 3 //指定座位的玩家
+)
+
+// Enum value maps for SCOPE.
+var (
+	SCOPE_name = map[int32]string{
+		0: "SCOPE_All",
+		1: "SCOPE_Team",
+		2: "SCOPE_Others",
+		3: "SCOPE_Seat",
+	}
+	SCOPE_value = map[string]int32{
+		"SCOPE_All":    0,
+		"SCOPE_Team":   1,
+		"SCOPE_Others": 2,
+		"SCOPE_Seat":   3,
+	}
+)
+
+func (x SCOPE) Enum() *SCOPE {
+	p := new(SCOPE)
+	*p = x
+	return p
+}
+
+func (x SCOPE) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SCOPE) Descriptor() protoreflect.EnumDescriptor {
+	return file_message_proto_enumTypes[2].Descriptor()
+}
+
+func (SCOPE) Type() protoreflect.EnumType {
+	return &file_message_proto_enumTypes[2]
+}
+
+func (x SCOPE) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SCOPE.Descriptor instead.
+func (SCOPE) EnumDescriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{2}
+}
+
+//服务端生成的系统事件
+type EVENT int32
+
+const (
+	EVENT_EVT_None        EVENT = 0 //普通输入
+	EVENT_EVT_Join        EVENT = 1 //新玩家加入(新增座位)
+	EVENT_EVT_Remove      EVENT = 2 //玩家被移除(座位删除)
+	EVENT_EVT_Disconnect  EVENT = 3 //玩家掉线
+	EVENT_EVT_Reconnect   EVENT = 4 //玩家重连回来
+	EVENT_EVT_Surrender   EVENT = 5 //玩家投降
+	EVENT_EVT_Leave       EVENT = 6 //玩家主动离开
+	EVENT_EVT_TurnTimeout EVENT = 7 //回合制模式下玩家这一帧超时没有输入
+)
+
+// Enum value maps for EVENT.
+var (
+	EVENT_name = map[int32]string{
+		0: "EVT_None",
+		1: "EVT_Join",
+		2: "EVT_Remove",
+		3: "EVT_Disconnect",
+		4: "EVT_Reconnect",
+		5: "EVT_Surrender",
+		6: "EVT_Leave",
+		7: "EVT_TurnTimeout",
+	}
+	EVENT_value = map[string]int32{
+		"EVT_None":        0,
+		"EVT_Join":        1,
+		"EVT_Remove":      2,
+		"EVT_Disconnect":  3,
+		"EVT_Reconnect":   4,
+		"EVT_Surrender":   5,
+		"EVT_Leave":       6,
+		"EVT_TurnTimeout": 7,
+	}
+)
+
+func (x EVENT) Enum() *EVENT {
+	p := new(EVENT)
+	*p = x
+	return p
+}
+
+func (x EVENT) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EVENT) Descriptor() protoreflect.EnumDescriptor {
+	return file_message_proto_enumTypes[3].Descriptor()
+}
+
+func (EVENT) Type() protoreflect.EnumType {
+	return &file_message_proto_enumTypes[3]
+}
+
+func (x EVENT) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EVENT.Descriptor instead.
+func (EVENT) EnumDescriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{3}
+}
+
+//帧同步模式
+type MODE int32
+
+const (
+	MODE_MODE_Relay MODE = 0 //固定帧率，每帧按时结束
+	MODE_MODE_Turn  MODE = 1 //回合制，所有在线玩家都输入了(或者超时)这一帧才结束
+)
+
+// Enum value maps for MODE.
+var (
+	MODE_name = map[int32]string{
+		0: "MODE_Relay",
+		1: "MODE_Turn",
+	}
+	MODE_value = map[string]int32{
+		"MODE_Relay": 0,
+		"MODE_Turn":  1,
+	}
+)
+
+func (x MODE) Enum() *MODE {
+	p := new(MODE)
+	*p = x
+	return p
+}
+
+func (x MODE) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MODE) Descriptor() protoreflect.EnumDescriptor {
+	return file_message_proto_enumTypes[4].Descriptor()
+}
+
+func (MODE) Type() protoreflect.EnumType {
+	return &file_message_proto_enumTypes[4]
+}
+
+func (x MODE) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MODE.Descriptor instead.
+func (MODE) EnumDescriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{4}
+}
+
+//房间关闭原因
+type CLOSE int32
+
+const (
+	CLOSE_CLOSE_Over    CLOSE = 0 //正常结束
+	CLOSE_CLOSE_Timeout CLOSE = 1 //超时
+	CLOSE_CLOSE_Crash   CLOSE = 2 //服务端房间出错
+)
+
+// Enum value maps for CLOSE.
+var (
+	CLOSE_name = map[int32]string{
+		0: "CLOSE_Over",
+		1: "CLOSE_Timeout",
+		2: "CLOSE_Crash",
+	}
+	CLOSE_value = map[string]int32{
+		"CLOSE_Over":    0,
+		"CLOSE_Timeout": 1,
+		"CLOSE_Crash":   2,
+	}
+)
+
+func (x CLOSE) Enum() *CLOSE {
+	p := new(CLOSE)
+	*p = x
+	return p
+}
+
+func (x CLOSE) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CLOSE) Descriptor() protoreflect.EnumDescriptor {
+	return file_message_proto_enumTypes[5].Descriptor()
+}
+
+func (CLOSE) Type() protoreflect.EnumType {
+	return &file_message_proto_enumTypes[5]
+}
+
+func (x CLOSE) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CLOSE.Descriptor instead.
+func (CLOSE) EnumDescriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{5}
+}
+
 //客户端发来的第一个消息
 type C2S_ConnectMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PlayerID *uint64 `protobuf:"varint,1,opt,name=playerID,proto3,oneof" json:"playerID,omitempty"` //唯一ID
-	BattleID *uint64 `protobuf:"varint,2,opt,name=battleID,proto3,oneof" json:"battleID,omitempty"` //战斗ID
-	Token    *string `protobuf:"bytes,10,opt,name=token,proto3,oneof" json:"token,omitempty"`       //令牌
+	PlayerID  *uint64 `protobuf:"varint,1,opt,name=playerID,proto3,oneof" json:"playerID,omitempty"`    //唯一ID
+	BattleID  *uint64 `protobuf:"varint,2,opt,name=battleID,proto3,oneof" json:"battleID,omitempty"`    //战斗ID
+	Token     *string `protobuf:"bytes,10,opt,name=token,proto3,oneof" json:"token,omitempty"`          //令牌
+	Spectator *bool   `protobuf:"varint,11,opt,name=spectator,proto3,oneof" json:"spectator,omitempty"` //是否观战(token填房间的观战令牌)
 }
 
 func (x *C2S_ConnectMsg) Reset() {
@@ -217,6 +493,13 @@ func (x *C2S_ConnectMsg) GetToken() string {
 	return ""
 }
 
+func (x *C2S_ConnectMsg) GetSpectator() bool {
+	if x != nil && x.Spectator != nil {
+		return *x.Spectator
+	}
+	return false
+}
+
 //服务端返回连接结果
 type S2C_ConnectMsg struct {
 	state         protoimpl.MessageState
@@ -271,7 +554,7 @@ type S2C_JoinRoomMsg struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Roomseatid *int32   `protobuf:"varint,1,opt,name=roomseatid,proto3,oneof" json:"roomseatid,omitempty"` //自己的位置索引id(1~N)
+	Roomseatid *int32   `protobuf:"varint,1,opt,name=roomseatid,proto3,oneof" json:"roomseatid,omitempty"` //自己的位置索引id(1~N，观战者为0)
 	Others     []uint64 `protobuf:"varint,2,rep,packed,name=others,proto3" json:"others,omitempty"`        //其他人的id
 	Pros       []int32  `protobuf:"varint,3,rep,packed,name=pros,proto3" json:"pros,omitempty"`            //其他人的进度
 	RandomSeed *int32   `protobuf:"varint,4,opt,name=randomSeed,proto3,oneof" json:"randomSeed,omitempty"` //随机种子
@@ -343,7 +626,11 @@ type S2C_StartMsg struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TimeStamp *int64 `protobuf:"varint,1,opt,name=timeStamp,proto3,oneof" json:"timeStamp,omitempty"` //同步时间戳
+	TimeStamp     *int64  `protobuf:"varint,1,opt,name=timeStamp,proto3,oneof" json:"timeStamp,omitempty"`         //同步时间戳(秒)
+	TimeStampMs   *int64  `protobuf:"varint,2,opt,name=timeStampMs,proto3,oneof" json:"timeStampMs,omitempty"`     //同步时间戳(毫秒)
+	Mode          *MODE   `protobuf:"varint,3,opt,name=mode,proto3,enum=pb.MODE,oneof" json:"mode,omitempty"`      //帧同步模式
+	TurnTimeoutMs *int64  `protobuf:"varint,4,opt,name=turnTimeoutMs,proto3,oneof" json:"turnTimeoutMs,omitempty"` //回合制模式下每帧的超时时间(毫秒，0不超时)
+	TickRate      *uint32 `protobuf:"varint,5,opt,name=tickRate,proto3,oneof" json:"tickRate,omitempty"`           //每秒多少帧
 }
 
 func (x *S2C_StartMsg) Reset() {
@@ -385,17 +672,50 @@ func (x *S2C_StartMsg) GetTimeStamp() int64 {
 	return 0
 }
 
-//读条进度
-type C2S_ProgressMsg struct {
+func (x *S2C_StartMsg) GetTimeStampMs() int64 {
+	if x != nil && x.TimeStampMs != nil {
+		return *x.TimeStampMs
+	}
+	return 0
+}
+
+func (x *S2C_StartMsg) GetMode() MODE {
+	if x != nil && x.Mode != nil {
+		return *x.Mode
+	}
+	return MODE_MODE_Relay
+}
+
+func (x *S2C_StartMsg) GetTurnTimeoutMs() int64 {
+	if x != nil && x.TurnTimeoutMs != nil {
+		return *x.TurnTimeoutMs
+	}
+	return 0
+}
+
+func (x *S2C_StartMsg) GetTickRate() uint32 {
+	if x != nil && x.TickRate != nil {
+		return *x.TickRate
+	}
+	return 0
+}
+
+//准备阶段状态和开始倒计时
+type S2C_CountdownMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Pro *int32 `protobuf:"varint,1,opt,name=pro,proto3,oneof" json:"pro,omitempty"` //进度值0~100
+	ReadyDeadlineMs *int64  `protobuf:"varint,1,opt,name=readyDeadlineMs,proto3,oneof" json:"readyDeadlineMs,omitempty"` //准备截止时间(毫秒)，到时间准备好的人数够了就开始
+	StartTimeMs     *int64  `protobuf:"varint,2,opt,name=startTimeMs,proto3,oneof" json:"startTimeMs,omitempty"`         //倒计时结束开始游戏的时间(毫秒)，0表示还没开始倒计时
+	Ready           *uint32 `protobuf:"varint,3,opt,name=ready,proto3,oneof" json:"ready,omitempty"`                     //已经准备好的人数
+	Total           *uint32 `protobuf:"varint,4,opt,name=total,proto3,oneof" json:"total,omitempty"`                     //总人数
+	MinReady        *uint32 `protobuf:"varint,5,opt,name=minReady,proto3,oneof" json:"minReady,omitempty"`               //最少需要多少人准备好
+	HostID          *uint64 `protobuf:"varint,6,opt,name=hostID,proto3,oneof" json:"hostID,omitempty"`                   //房主开始模式下的房主ID(0表示不是这个模式)
 }
 
-func (x *C2S_ProgressMsg) Reset() {
-	*x = C2S_ProgressMsg{}
+func (x *S2C_CountdownMsg) Reset() {
+	*x = S2C_CountdownMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_message_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -403,13 +723,13 @@ func (x *C2S_ProgressMsg) Reset() {
 	}
 }
 
-func (x *C2S_ProgressMsg) String() string {
+func (x *S2C_CountdownMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*C2S_ProgressMsg) ProtoMessage() {}
+func (*S2C_CountdownMsg) ProtoMessage() {}
 
-func (x *C2S_ProgressMsg) ProtoReflect() protoreflect.Message {
+func (x *S2C_CountdownMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_message_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -421,30 +741,67 @@ func (x *C2S_ProgressMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use C2S_ProgressMsg.ProtoReflect.Descriptor instead.
-func (*C2S_ProgressMsg) Descriptor() ([]byte, []int) {
+// Deprecated: Use S2C_CountdownMsg.ProtoReflect.Descriptor instead.
+func (*S2C_CountdownMsg) Descriptor() ([]byte, []int) {
 	return file_message_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *C2S_ProgressMsg) GetPro() int32 {
-	if x != nil && x.Pro != nil {
-		return *x.Pro
+func (x *S2C_CountdownMsg) GetReadyDeadlineMs() int64 {
+	if x != nil && x.ReadyDeadlineMs != nil {
+		return *x.ReadyDeadlineMs
 	}
 	return 0
 }
 
-//读条进度
-type S2C_ProgressMsg struct {
+func (x *S2C_CountdownMsg) GetStartTimeMs() int64 {
+	if x != nil && x.StartTimeMs != nil {
+		return *x.StartTimeMs
+	}
+	return 0
+}
+
+func (x *S2C_CountdownMsg) GetReady() uint32 {
+	if x != nil && x.Ready != nil {
+		return *x.Ready
+	}
+	return 0
+}
+
+func (x *S2C_CountdownMsg) GetTotal() uint32 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
+func (x *S2C_CountdownMsg) GetMinReady() uint32 {
+	if x != nil && x.MinReady != nil {
+		return *x.MinReady
+	}
+	return 0
+}
+
+func (x *S2C_CountdownMsg) GetHostID() uint64 {
+	if x != nil && x.HostID != nil {
+		return *x.HostID
+	}
+	return 0
+}
+
+//客户端发起ping
+type C2S_PingMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id  *uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`   //id
-	Pro *int32  `protobuf:"varint,2,opt,name=pro,proto3,oneof" json:"pro,omitempty"` //进度值0~100
+	ClientTime *int64  `protobuf:"varint,1,opt,name=clientTime,proto3,oneof" json:"clientTime,omitempty"` //客户端发送时间(毫秒)
+	EchoTime   *int64  `protobuf:"varint,2,opt,name=echoTime,proto3,oneof" json:"echoTime,omitempty"`     //上一个S2C_PingMsg的serverSendTime，没有填0
+	HoldTime   *int64  `protobuf:"varint,3,opt,name=holdTime,proto3,oneof" json:"holdTime,omitempty"`     //客户端收到上一个S2C_PingMsg到发送本消息经过的时间(毫秒)
+	AckFrameID *uint32 `protobuf:"varint,4,opt,name=ackFrameID,proto3,oneof" json:"ackFrameID,omitempty"` //客户端已经收到的帧数(下一个想要的帧ID)
 }
 
-func (x *S2C_ProgressMsg) Reset() {
-	*x = S2C_ProgressMsg{}
+func (x *C2S_PingMsg) Reset() {
+	*x = C2S_PingMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_message_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -452,13 +809,13 @@ func (x *S2C_ProgressMsg) Reset() {
 	}
 }
 
-func (x *S2C_ProgressMsg) String() string {
+func (x *C2S_PingMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*S2C_ProgressMsg) ProtoMessage() {}
+func (*C2S_PingMsg) ProtoMessage() {}
 
-func (x *S2C_ProgressMsg) ProtoReflect() protoreflect.Message {
+func (x *C2S_PingMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_message_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -470,39 +827,54 @@ func (x *S2C_ProgressMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use S2C_ProgressMsg.ProtoReflect.Descriptor instead.
-func (*S2C_ProgressMsg) Descriptor() ([]byte, []int) {
+// Deprecated: Use C2S_PingMsg.ProtoReflect.Descriptor instead.
+func (*C2S_PingMsg) Descriptor() ([]byte, []int) {
 	return file_message_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *S2C_ProgressMsg) GetId() uint64 {
-	if x != nil && x.Id != nil {
-		return *x.Id
+func (x *C2S_PingMsg) GetClientTime() int64 {
+	if x != nil && x.ClientTime != nil {
+		return *x.ClientTime
 	}
 	return 0
 }
 
-func (x *S2C_ProgressMsg) GetPro() int32 {
-	if x != nil && x.Pro != nil {
-		return *x.Pro
+func (x *C2S_PingMsg) GetEchoTime() int64 {
+	if x != nil && x.EchoTime != nil {
+		return *x.EchoTime
 	}
 	return 0
 }
 
-//操作输入消息
-type C2S_InputMsg struct {
+func (x *C2S_PingMsg) GetHoldTime() int64 {
+	if x != nil && x.HoldTime != nil {
+		return *x.HoldTime
+	}
+	return 0
+}
+
+func (x *C2S_PingMsg) GetAckFrameID() uint32 {
+	if x != nil && x.AckFrameID != nil {
+		return *x.AckFrameID
+	}
+	return 0
+}
+
+//服务端回复ping
+//客户端收到的时间为t3，时钟偏差offset=((serverRecvTime-clientTime)+(serverSendTime-t3))/2
+type S2C_PingMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sid     *int32  `protobuf:"varint,1,opt,name=sid,proto3,oneof" json:"sid,omitempty"`         //操作id
-	X       *int32  `protobuf:"varint,2,opt,name=x,proto3,oneof" json:"x,omitempty"`             //操作位置x
-	Y       *int32  `protobuf:"varint,3,opt,name=y,proto3,oneof" json:"y,omitempty"`             //操作位置y
-	FrameID *uint32 `protobuf:"varint,4,opt,name=frameID,proto3,oneof" json:"frameID,omitempty"` //帧ID
+	ClientTime     *int64  `protobuf:"varint,1,opt,name=clientTime,proto3,oneof" json:"clientTime,omitempty"`         //原样返回C2S_PingMsg.clientTime
+	ServerRecvTime *int64  `protobuf:"varint,2,opt,name=serverRecvTime,proto3,oneof" json:"serverRecvTime,omitempty"` //服务端收到的时间(毫秒)
+	ServerSendTime *int64  `protobuf:"varint,3,opt,name=serverSendTime,proto3,oneof" json:"serverSendTime,omitempty"` //服务端发送的时间(毫秒)
+	Rtt            *uint32 `protobuf:"varint,4,opt,name=rtt,proto3,oneof" json:"rtt,omitempty"`                       //服务端测得的平滑RTT(毫秒)
 }
 
-func (x *C2S_InputMsg) Reset() {
-	*x = C2S_InputMsg{}
+func (x *S2C_PingMsg) Reset() {
+	*x = S2C_PingMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_message_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -510,13 +882,13 @@ func (x *C2S_InputMsg) Reset() {
 	}
 }
 
-func (x *C2S_InputMsg) String() string {
+func (x *S2C_PingMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*C2S_InputMsg) ProtoMessage() {}
+func (*S2C_PingMsg) ProtoMessage() {}
 
-func (x *C2S_InputMsg) ProtoReflect() protoreflect.Message {
+func (x *S2C_PingMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_message_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -528,54 +900,50 @@ func (x *C2S_InputMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use C2S_InputMsg.ProtoReflect.Descriptor instead.
-func (*C2S_InputMsg) Descriptor() ([]byte, []int) {
+// Deprecated: Use S2C_PingMsg.ProtoReflect.Descriptor instead.
+func (*S2C_PingMsg) Descriptor() ([]byte, []int) {
 	return file_message_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *C2S_InputMsg) GetSid() int32 {
-	if x != nil && x.Sid != nil {
-		return *x.Sid
+func (x *S2C_PingMsg) GetClientTime() int64 {
+	if x != nil && x.ClientTime != nil {
+		return *x.ClientTime
 	}
 	return 0
 }
 
-func (x *C2S_InputMsg) GetX() int32 {
-	if x != nil && x.X != nil {
-		return *x.X
+func (x *S2C_PingMsg) GetServerRecvTime() int64 {
+	if x != nil && x.ServerRecvTime != nil {
+		return *x.ServerRecvTime
 	}
 	return 0
 }
 
-func (x *C2S_InputMsg) GetY() int32 {
-	if x != nil && x.Y != nil {
-		return *x.Y
+func (x *S2C_PingMsg) GetServerSendTime() int64 {
+	if x != nil && x.ServerSendTime != nil {
+		return *x.ServerSendTime
 	}
 	return 0
 }
 
-func (x *C2S_InputMsg) GetFrameID() uint32 {
-	if x != nil && x.FrameID != nil {
-		return *x.FrameID
+func (x *S2C_PingMsg) GetRtt() uint32 {
+	if x != nil && x.Rtt != nil {
+		return *x.Rtt
 	}
 	return 0
 }
 
-//帧存储操作输入
-type InputData struct {
+//读条进度
+type C2S_ProgressMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id         *uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`                 //id
-	Sid        *int32  `protobuf:"varint,2,opt,name=sid,proto3,oneof" json:"sid,omitempty"`               //操作id
-	X          *int32  `protobuf:"varint,3,opt,name=x,proto3,oneof" json:"x,omitempty"`                   //操作位置x
-	Y          *int32  `protobuf:"varint,4,opt,name=y,proto3,oneof" json:"y,omitempty"`                   //操作位置y
-	Roomseatid *int32  `protobuf:"varint,5,opt,name=roomseatid,proto3,oneof" json:"roomseatid,omitempty"` //操作者的位置索引id(1~N)
+	Pro *int32 `protobuf:"varint,1,opt,name=pro,proto3,oneof" json:"pro,omitempty"` //进度值0~100
 }
 
-func (x *InputData) Reset() {
-	*x = InputData{}
+func (x *C2S_ProgressMsg) Reset() {
+	*x = C2S_ProgressMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_message_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -583,13 +951,13 @@ func (x *InputData) Reset() {
 	}
 }
 
-func (x *InputData) String() string {
+func (x *C2S_ProgressMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InputData) ProtoMessage() {}
+func (*C2S_ProgressMsg) ProtoMessage() {}
 
-func (x *InputData) ProtoReflect() protoreflect.Message {
+func (x *C2S_ProgressMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_message_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -601,37 +969,298 @@ func (x *InputData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InputData.ProtoReflect.Descriptor instead.
-func (*InputData) Descriptor() ([]byte, []int) {
+// Deprecated: Use C2S_ProgressMsg.ProtoReflect.Descriptor instead.
+func (*C2S_ProgressMsg) Descriptor() ([]byte, []int) {
 	return file_message_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *InputData) GetId() uint64 {
-	if x != nil && x.Id != nil {
-		return *x.Id
+func (x *C2S_ProgressMsg) GetPro() int32 {
+	if x != nil && x.Pro != nil {
+		return *x.Pro
 	}
 	return 0
 }
 
-func (x *InputData) GetSid() int32 {
-	if x != nil && x.Sid != nil {
-		return *x.Sid
-	}
-	return 0
+//读条进度
+type S2C_ProgressMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id  *uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`   //id
+	Pro *int32  `protobuf:"varint,2,opt,name=pro,proto3,oneof" json:"pro,omitempty"` //进度值0~100
 }
 
-func (x *InputData) GetX() int32 {
-	if x != nil && x.X != nil {
-		return *x.X
+func (x *S2C_ProgressMsg) Reset() {
+	*x = S2C_ProgressMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *InputData) GetY() int32 {
-	if x != nil && x.Y != nil {
-		return *x.Y
-	}
-	return 0
+func (x *S2C_ProgressMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_ProgressMsg) ProtoMessage() {}
+
+func (x *S2C_ProgressMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_ProgressMsg.ProtoReflect.Descriptor instead.
+func (*S2C_ProgressMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *S2C_ProgressMsg) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *S2C_ProgressMsg) GetPro() int32 {
+	if x != nil && x.Pro != nil {
+		return *x.Pro
+	}
+	return 0
+}
+
+//操作输入消息
+type C2S_InputMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sid     *int32          `protobuf:"varint,1,opt,name=sid,proto3,oneof" json:"sid,omitempty"`         //操作id
+	X       *int32          `protobuf:"varint,2,opt,name=x,proto3,oneof" json:"x,omitempty"`             //操作位置x
+	Y       *int32          `protobuf:"varint,3,opt,name=y,proto3,oneof" json:"y,omitempty"`             //操作位置y
+	FrameID *uint32         `protobuf:"varint,4,opt,name=frameID,proto3,oneof" json:"frameID,omitempty"` //帧ID
+	Seq     *uint32         `protobuf:"varint,5,opt,name=seq,proto3,oneof" json:"seq,omitempty"`         //输入序号(从1开始递增，0表示不去重)
+	History []*C2S_InputMsg `protobuf:"bytes,6,rep,name=history,proto3" json:"history,omitempty"`        //冗余发送的最近几个输入(从旧到新)
+}
+
+func (x *C2S_InputMsg) Reset() {
+	*x = C2S_InputMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *C2S_InputMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*C2S_InputMsg) ProtoMessage() {}
+
+func (x *C2S_InputMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use C2S_InputMsg.ProtoReflect.Descriptor instead.
+func (*C2S_InputMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *C2S_InputMsg) GetSid() int32 {
+	if x != nil && x.Sid != nil {
+		return *x.Sid
+	}
+	return 0
+}
+
+func (x *C2S_InputMsg) GetX() int32 {
+	if x != nil && x.X != nil {
+		return *x.X
+	}
+	return 0
+}
+
+func (x *C2S_InputMsg) GetY() int32 {
+	if x != nil && x.Y != nil {
+		return *x.Y
+	}
+	return 0
+}
+
+func (x *C2S_InputMsg) GetFrameID() uint32 {
+	if x != nil && x.FrameID != nil {
+		return *x.FrameID
+	}
+	return 0
+}
+
+func (x *C2S_InputMsg) GetSeq() uint32 {
+	if x != nil && x.Seq != nil {
+		return *x.Seq
+	}
+	return 0
+}
+
+func (x *C2S_InputMsg) GetHistory() []*C2S_InputMsg {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+//输入被拒绝消息
+type S2C_InputRejectMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FrameID    *uint32    `protobuf:"varint,1,opt,name=frameID,proto3,oneof" json:"frameID,omitempty"`                       //客户端请求的帧ID
+	CurFrameID *uint32    `protobuf:"varint,2,opt,name=curFrameID,proto3,oneof" json:"curFrameID,omitempty"`                 //服务端当前帧ID
+	ErrorCode  *ERRORCODE `protobuf:"varint,3,opt,name=errorCode,proto3,enum=pb.ERRORCODE,oneof" json:"errorCode,omitempty"` //错误码
+}
+
+func (x *S2C_InputRejectMsg) Reset() {
+	*x = S2C_InputRejectMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *S2C_InputRejectMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_InputRejectMsg) ProtoMessage() {}
+
+func (x *S2C_InputRejectMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_InputRejectMsg.ProtoReflect.Descriptor instead.
+func (*S2C_InputRejectMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *S2C_InputRejectMsg) GetFrameID() uint32 {
+	if x != nil && x.FrameID != nil {
+		return *x.FrameID
+	}
+	return 0
+}
+
+func (x *S2C_InputRejectMsg) GetCurFrameID() uint32 {
+	if x != nil && x.CurFrameID != nil {
+		return *x.CurFrameID
+	}
+	return 0
+}
+
+func (x *S2C_InputRejectMsg) GetErrorCode() ERRORCODE {
+	if x != nil && x.ErrorCode != nil {
+		return *x.ErrorCode
+	}
+	return ERRORCODE_ERR_Ok
+}
+
+//帧存储操作输入
+type InputData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         *uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`                     //id
+	Sid        *int32  `protobuf:"varint,2,opt,name=sid,proto3,oneof" json:"sid,omitempty"`                   //操作id
+	X          *int32  `protobuf:"varint,3,opt,name=x,proto3,oneof" json:"x,omitempty"`                       //操作位置x
+	Y          *int32  `protobuf:"varint,4,opt,name=y,proto3,oneof" json:"y,omitempty"`                       //操作位置y
+	Roomseatid *int32  `protobuf:"varint,5,opt,name=roomseatid,proto3,oneof" json:"roomseatid,omitempty"`     //操作者的位置索引id(1~N)
+	Event      *EVENT  `protobuf:"varint,6,opt,name=event,proto3,enum=pb.EVENT,oneof" json:"event,omitempty"` //系统事件，不为EVT_None时是服务端生成的输入
+}
+
+func (x *InputData) Reset() {
+	*x = InputData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InputData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputData) ProtoMessage() {}
+
+func (x *InputData) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputData.ProtoReflect.Descriptor instead.
+func (*InputData) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *InputData) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *InputData) GetSid() int32 {
+	if x != nil && x.Sid != nil {
+		return *x.Sid
+	}
+	return 0
+}
+
+func (x *InputData) GetX() int32 {
+	if x != nil && x.X != nil {
+		return *x.X
+	}
+	return 0
+}
+
+func (x *InputData) GetY() int32 {
+	if x != nil && x.Y != nil {
+		return *x.Y
+	}
+	return 0
 }
 
 func (x *InputData) GetRoomseatid() int32 {
@@ -641,6 +1270,13 @@ func (x *InputData) GetRoomseatid() int32 {
 	return 0
 }
 
+func (x *InputData) GetEvent() EVENT {
+	if x != nil && x.Event != nil {
+		return *x.Event
+	}
+	return EVENT_EVT_None
+}
+
 //帧数据
 type FrameData struct {
 	state         protoimpl.MessageState
@@ -654,7 +1290,7 @@ type FrameData struct {
 func (x *FrameData) Reset() {
 	*x = FrameData{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_message_proto_msgTypes[8]
+		mi := &file_message_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -667,7 +1303,7 @@ func (x *FrameData) String() string {
 func (*FrameData) ProtoMessage() {}
 
 func (x *FrameData) ProtoReflect() protoreflect.Message {
-	mi := &file_message_proto_msgTypes[8]
+	mi := &file_message_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -680,7 +1316,7 @@ func (x *FrameData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FrameData.ProtoReflect.Descriptor instead.
 func (*FrameData) Descriptor() ([]byte, []int) {
-	return file_message_proto_rawDescGZIP(), []int{8}
+	return file_message_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *FrameData) GetFrameID() uint32 {
@@ -692,37 +1328,1084 @@ func (x *FrameData) GetFrameID() uint32 {
 
 func (x *FrameData) GetInput() []*InputData {
 	if x != nil {
-		return x.Input
+		return x.Input
+	}
+	return nil
+}
+
+//广播帧消息
+type S2C_FrameMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Frames      []*FrameData `protobuf:"bytes,1,rep,name=frames,proto3" json:"frames,omitempty"`                  //帧数据(只包含非空帧)
+	FromFrameID *uint32      `protobuf:"varint,2,opt,name=fromFrameID,proto3,oneof" json:"fromFrameID,omitempty"` //本消息覆盖的起始帧ID(包含)
+	ToFrameID   *uint32      `protobuf:"varint,3,opt,name=toFrameID,proto3,oneof" json:"toFrameID,omitempty"`     //本消息覆盖的结束帧ID(不包含)，范围内没有出现的帧都是空帧
+}
+
+func (x *S2C_FrameMsg) Reset() {
+	*x = S2C_FrameMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *S2C_FrameMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_FrameMsg) ProtoMessage() {}
+
+func (x *S2C_FrameMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_FrameMsg.ProtoReflect.Descriptor instead.
+func (*S2C_FrameMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *S2C_FrameMsg) GetFrames() []*FrameData {
+	if x != nil {
+		return x.Frames
+	}
+	return nil
+}
+
+func (x *S2C_FrameMsg) GetFromFrameID() uint32 {
+	if x != nil && x.FromFrameID != nil {
+		return *x.FromFrameID
+	}
+	return 0
+}
+
+func (x *S2C_FrameMsg) GetToFrameID() uint32 {
+	if x != nil && x.ToFrameID != nil {
+		return *x.ToFrameID
+	}
+	return 0
+}
+
+//单个玩家的结算
+type PlayerResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    *uint64          `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`                                                                                         //玩家ID
+	Rank  *int32           `protobuf:"varint,2,opt,name=rank,proto3,oneof" json:"rank,omitempty"`                                                                                     //名次(1~N, 并列可以相同)
+	Team  *int32           `protobuf:"varint,3,opt,name=team,proto3,oneof" json:"team,omitempty"`                                                                                     //队伍
+	Score *int64           `protobuf:"varint,4,opt,name=score,proto3,oneof" json:"score,omitempty"`                                                                                   //得分
+	Stats map[string]int64 `protobuf:"bytes,5,rep,name=stats,proto3" json:"stats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"` //自定义统计(击杀、伤害等)
+}
+
+func (x *PlayerResult) Reset() {
+	*x = PlayerResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerResult) ProtoMessage() {}
+
+func (x *PlayerResult) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerResult.ProtoReflect.Descriptor instead.
+func (*PlayerResult) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PlayerResult) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *PlayerResult) GetRank() int32 {
+	if x != nil && x.Rank != nil {
+		return *x.Rank
+	}
+	return 0
+}
+
+func (x *PlayerResult) GetTeam() int32 {
+	if x != nil && x.Team != nil {
+		return *x.Team
+	}
+	return 0
+}
+
+func (x *PlayerResult) GetScore() int64 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+func (x *PlayerResult) GetStats() map[string]int64 {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+//客户端上报状态hash
+type C2S_HashMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FrameID *uint32 `protobuf:"varint,1,opt,name=frameID,proto3,oneof" json:"frameID,omitempty"` //执行完这一帧之后的状态
+	Hash    *uint64 `protobuf:"varint,2,opt,name=hash,proto3,oneof" json:"hash,omitempty"`       //状态hash
+}
+
+func (x *C2S_HashMsg) Reset() {
+	*x = C2S_HashMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *C2S_HashMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*C2S_HashMsg) ProtoMessage() {}
+
+func (x *C2S_HashMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use C2S_HashMsg.ProtoReflect.Descriptor instead.
+func (*C2S_HashMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *C2S_HashMsg) GetFrameID() uint32 {
+	if x != nil && x.FrameID != nil {
+		return *x.FrameID
+	}
+	return 0
+}
+
+func (x *C2S_HashMsg) GetHash() uint64 {
+	if x != nil && x.Hash != nil {
+		return *x.Hash
+	}
+	return 0
+}
+
+//客户端状态和服务端模拟不一致
+type S2C_HashMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FrameID    *uint32 `protobuf:"varint,1,opt,name=frameID,proto3,oneof" json:"frameID,omitempty"`       //帧ID
+	Hash       *uint64 `protobuf:"varint,2,opt,name=hash,proto3,oneof" json:"hash,omitempty"`             //服务端模拟的状态hash
+	ClientHash *uint64 `protobuf:"varint,3,opt,name=clientHash,proto3,oneof" json:"clientHash,omitempty"` //客户端上报的hash
+}
+
+func (x *S2C_HashMsg) Reset() {
+	*x = S2C_HashMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *S2C_HashMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_HashMsg) ProtoMessage() {}
+
+func (x *S2C_HashMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_HashMsg.ProtoReflect.Descriptor instead.
+func (*S2C_HashMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *S2C_HashMsg) GetFrameID() uint32 {
+	if x != nil && x.FrameID != nil {
+		return *x.FrameID
+	}
+	return 0
+}
+
+func (x *S2C_HashMsg) GetHash() uint64 {
+	if x != nil && x.Hash != nil {
+		return *x.Hash
+	}
+	return 0
+}
+
+func (x *S2C_HashMsg) GetClientHash() uint64 {
+	if x != nil && x.ClientHash != nil {
+		return *x.ClientHash
+	}
+	return 0
+}
+
+//客户端上传状态快照的一个分片(按offset顺序发)
+//整个消息(不算3字节包头)不能超过1024字节，超过服务端会断开连接，data每片建议不超过893字节(和下发的一样)
+type C2S_SnapshotMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FrameID *uint32 `protobuf:"varint,1,opt,name=frameID,proto3,oneof" json:"frameID,omitempty"` //执行完这一帧之后的状态
+	Hash    *uint64 `protobuf:"varint,2,opt,name=hash,proto3,oneof" json:"hash,omitempty"`       //状态hash
+	Total   *uint32 `protobuf:"varint,3,opt,name=total,proto3,oneof" json:"total,omitempty"`     //快照总大小(字节)
+	Offset  *uint32 `protobuf:"varint,4,opt,name=offset,proto3,oneof" json:"offset,omitempty"`   //这个分片在快照里的位置
+	Data    []byte  `protobuf:"bytes,5,opt,name=data,proto3,oneof" json:"data,omitempty"`        //分片数据
+}
+
+func (x *C2S_SnapshotMsg) Reset() {
+	*x = C2S_SnapshotMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *C2S_SnapshotMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*C2S_SnapshotMsg) ProtoMessage() {}
+
+func (x *C2S_SnapshotMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use C2S_SnapshotMsg.ProtoReflect.Descriptor instead.
+func (*C2S_SnapshotMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *C2S_SnapshotMsg) GetFrameID() uint32 {
+	if x != nil && x.FrameID != nil {
+		return *x.FrameID
+	}
+	return 0
+}
+
+func (x *C2S_SnapshotMsg) GetHash() uint64 {
+	if x != nil && x.Hash != nil {
+		return *x.Hash
+	}
+	return 0
+}
+
+func (x *C2S_SnapshotMsg) GetTotal() uint32 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
+func (x *C2S_SnapshotMsg) GetOffset() uint32 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
+func (x *C2S_SnapshotMsg) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+//重连时服务端下发状态快照的一个分片，发完之后只发这一帧之后的帧
+// data每片最多893字节，保证整个消息不超过1024字节
+type S2C_SnapshotMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FrameID *uint32 `protobuf:"varint,1,opt,name=frameID,proto3,oneof" json:"frameID,omitempty"` //执行完这一帧之后的状态
+	Hash    *uint64 `protobuf:"varint,2,opt,name=hash,proto3,oneof" json:"hash,omitempty"`       //状态hash
+	Total   *uint32 `protobuf:"varint,3,opt,name=total,proto3,oneof" json:"total,omitempty"`     //快照总大小(字节)
+	Offset  *uint32 `protobuf:"varint,4,opt,name=offset,proto3,oneof" json:"offset,omitempty"`   //这个分片在快照里的位置
+	Data    []byte  `protobuf:"bytes,5,opt,name=data,proto3,oneof" json:"data,omitempty"`        //分片数据
+}
+
+func (x *S2C_SnapshotMsg) Reset() {
+	*x = S2C_SnapshotMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *S2C_SnapshotMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_SnapshotMsg) ProtoMessage() {}
+
+func (x *S2C_SnapshotMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_SnapshotMsg.ProtoReflect.Descriptor instead.
+func (*S2C_SnapshotMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *S2C_SnapshotMsg) GetFrameID() uint32 {
+	if x != nil && x.FrameID != nil {
+		return *x.FrameID
+	}
+	return 0
+}
+
+func (x *S2C_SnapshotMsg) GetHash() uint64 {
+	if x != nil && x.Hash != nil {
+		return *x.Hash
+	}
+	return 0
+}
+
+func (x *S2C_SnapshotMsg) GetTotal() uint32 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
+func (x *S2C_SnapshotMsg) GetOffset() uint32 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
+func (x *S2C_SnapshotMsg) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+//房主踢人
+type C2S_KickMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     *uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`        //被踢的玩家ID
+	Reason *string `protobuf:"bytes,2,opt,name=reason,proto3,oneof" json:"reason,omitempty"` //原因
+}
+
+func (x *C2S_KickMsg) Reset() {
+	*x = C2S_KickMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *C2S_KickMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*C2S_KickMsg) ProtoMessage() {}
+
+func (x *C2S_KickMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use C2S_KickMsg.ProtoReflect.Descriptor instead.
+func (*C2S_KickMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *C2S_KickMsg) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *C2S_KickMsg) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+//被踢出房间
+type S2C_KickMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           *uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`                     //被踢的玩家ID
+	By           *uint64 `protobuf:"varint,2,opt,name=by,proto3,oneof" json:"by,omitempty"`                     //谁踢的(0表示服务端或者管理员)
+	Reason       *string `protobuf:"bytes,3,opt,name=reason,proto3,oneof" json:"reason,omitempty"`              //原因
+	RejoinTimeMs *int64  `protobuf:"varint,4,opt,name=rejoinTimeMs,proto3,oneof" json:"rejoinTimeMs,omitempty"` //可以重新进入的时间(毫秒)，0表示不能再进入
+}
+
+func (x *S2C_KickMsg) Reset() {
+	*x = S2C_KickMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *S2C_KickMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_KickMsg) ProtoMessage() {}
+
+func (x *S2C_KickMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_KickMsg.ProtoReflect.Descriptor instead.
+func (*S2C_KickMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *S2C_KickMsg) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *S2C_KickMsg) GetBy() uint64 {
+	if x != nil && x.By != nil {
+		return *x.By
+	}
+	return 0
+}
+
+func (x *S2C_KickMsg) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+func (x *S2C_KickMsg) GetRejoinTimeMs() int64 {
+	if x != nil && x.RejoinTimeMs != nil {
+		return *x.RejoinTimeMs
+	}
+	return 0
+}
+
+//结果消息
+type C2S_ResultMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WinnerID *uint64         `protobuf:"varint,1,opt,name=winnerID,proto3,oneof" json:"winnerID,omitempty"` //胜利者ID
+	Players  []*PlayerResult `protobuf:"bytes,2,rep,name=players,proto3" json:"players,omitempty"`          //每个玩家的结算
+	Blob     []byte          `protobuf:"bytes,3,opt,name=blob,proto3,oneof" json:"blob,omitempty"`          //服务端不解析的自定义数据
+}
+
+func (x *C2S_ResultMsg) Reset() {
+	*x = C2S_ResultMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *C2S_ResultMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*C2S_ResultMsg) ProtoMessage() {}
+
+func (x *C2S_ResultMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use C2S_ResultMsg.ProtoReflect.Descriptor instead.
+func (*C2S_ResultMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *C2S_ResultMsg) GetWinnerID() uint64 {
+	if x != nil && x.WinnerID != nil {
+		return *x.WinnerID
+	}
+	return 0
+}
+
+func (x *C2S_ResultMsg) GetPlayers() []*PlayerResult {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *C2S_ResultMsg) GetBlob() []byte {
+	if x != nil {
+		return x.Blob
+	}
+	return nil
+}
+
+//玩家网络状态变化
+type S2C_NetStateMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       *uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`             //玩家ID
+	Degraded *bool   `protobuf:"varint,2,opt,name=degraded,proto3,oneof" json:"degraded,omitempty"` //true网络变差 false恢复
+	Rtt      *uint32 `protobuf:"varint,3,opt,name=rtt,proto3,oneof" json:"rtt,omitempty"`           //平滑RTT(毫秒)
+}
+
+func (x *S2C_NetStateMsg) Reset() {
+	*x = S2C_NetStateMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *S2C_NetStateMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_NetStateMsg) ProtoMessage() {}
+
+func (x *S2C_NetStateMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_NetStateMsg.ProtoReflect.Descriptor instead.
+func (*S2C_NetStateMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *S2C_NetStateMsg) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *S2C_NetStateMsg) GetDegraded() bool {
+	if x != nil && x.Degraded != nil {
+		return *x.Degraded
+	}
+	return false
+}
+
+func (x *S2C_NetStateMsg) GetRtt() uint32 {
+	if x != nil && x.Rtt != nil {
+		return *x.Rtt
+	}
+	return 0
+}
+
+//请求暂停/恢复
+type C2S_PauseMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pause *bool `protobuf:"varint,1,opt,name=pause,proto3,oneof" json:"pause,omitempty"` //true暂停 false恢复
+}
+
+func (x *C2S_PauseMsg) Reset() {
+	*x = C2S_PauseMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *C2S_PauseMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*C2S_PauseMsg) ProtoMessage() {}
+
+func (x *C2S_PauseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use C2S_PauseMsg.ProtoReflect.Descriptor instead.
+func (*C2S_PauseMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *C2S_PauseMsg) GetPause() bool {
+	if x != nil && x.Pause != nil {
+		return *x.Pause
+	}
+	return false
+}
+
+//暂停状态通知
+type S2C_PauseMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Paused    *bool   `protobuf:"varint,1,opt,name=paused,proto3,oneof" json:"paused,omitempty"`       //当前是否暂停
+	Id        *uint64 `protobuf:"varint,2,opt,name=id,proto3,oneof" json:"id,omitempty"`               //触发者ID(0表示服务端或者管理员)
+	FrameID   *uint32 `protobuf:"varint,3,opt,name=frameID,proto3,oneof" json:"frameID,omitempty"`     //暂停/恢复时的帧ID
+	Votes     *uint32 `protobuf:"varint,4,opt,name=votes,proto3,oneof" json:"votes,omitempty"`         //投票暂停模式下当前票数
+	NeedVotes *uint32 `protobuf:"varint,5,opt,name=needVotes,proto3,oneof" json:"needVotes,omitempty"` //投票暂停模式下需要的票数
+}
+
+func (x *S2C_PauseMsg) Reset() {
+	*x = S2C_PauseMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *S2C_PauseMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_PauseMsg) ProtoMessage() {}
+
+func (x *S2C_PauseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_PauseMsg.ProtoReflect.Descriptor instead.
+func (*S2C_PauseMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *S2C_PauseMsg) GetPaused() bool {
+	if x != nil && x.Paused != nil {
+		return *x.Paused
+	}
+	return false
+}
+
+func (x *S2C_PauseMsg) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *S2C_PauseMsg) GetFrameID() uint32 {
+	if x != nil && x.FrameID != nil {
+		return *x.FrameID
+	}
+	return 0
+}
+
+func (x *S2C_PauseMsg) GetVotes() uint32 {
+	if x != nil && x.Votes != nil {
+		return *x.Votes
+	}
+	return 0
+}
+
+func (x *S2C_PauseMsg) GetNeedVotes() uint32 {
+	if x != nil && x.NeedVotes != nil {
+		return *x.NeedVotes
+	}
+	return 0
+}
+
+//聊天消息
+type C2S_ChatMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scope *SCOPE  `protobuf:"varint,1,opt,name=scope,proto3,enum=pb.SCOPE,oneof" json:"scope,omitempty"` //接收范围
+	Text  *string `protobuf:"bytes,2,opt,name=text,proto3,oneof" json:"text,omitempty"`                  //文字
+	Emote *int32  `protobuf:"varint,3,opt,name=emote,proto3,oneof" json:"emote,omitempty"`               //表情id(0没有)
+}
+
+func (x *C2S_ChatMsg) Reset() {
+	*x = C2S_ChatMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *C2S_ChatMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*C2S_ChatMsg) ProtoMessage() {}
+
+func (x *C2S_ChatMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use C2S_ChatMsg.ProtoReflect.Descriptor instead.
+func (*C2S_ChatMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *C2S_ChatMsg) GetScope() SCOPE {
+	if x != nil && x.Scope != nil {
+		return *x.Scope
+	}
+	return SCOPE_SCOPE_All
+}
+
+func (x *C2S_ChatMsg) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *C2S_ChatMsg) GetEmote() int32 {
+	if x != nil && x.Emote != nil {
+		return *x.Emote
+	}
+	return 0
+}
+
+//聊天消息
+type S2C_ChatMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrorCode   *ERRORCODE `protobuf:"varint,1,opt,name=errorCode,proto3,enum=pb.ERRORCODE,oneof" json:"errorCode,omitempty"` //不为ERR_Ok时表示自己发的消息被拒绝
+	Id          *uint64    `protobuf:"varint,2,opt,name=id,proto3,oneof" json:"id,omitempty"`                                 //发送者ID
+	Seat        *int32     `protobuf:"varint,3,opt,name=seat,proto3,oneof" json:"seat,omitempty"`                             //发送者座位号
+	Scope       *SCOPE     `protobuf:"varint,4,opt,name=scope,proto3,enum=pb.SCOPE,oneof" json:"scope,omitempty"`             //接收范围
+	Text        *string    `protobuf:"bytes,5,opt,name=text,proto3,oneof" json:"text,omitempty"`                              //文字
+	Emote       *int32     `protobuf:"varint,6,opt,name=emote,proto3,oneof" json:"emote,omitempty"`                           //表情id
+	TimeStampMs *int64     `protobuf:"varint,7,opt,name=timeStampMs,proto3,oneof" json:"timeStampMs,omitempty"`               //服务端收到的时间(毫秒)
+}
+
+func (x *S2C_ChatMsg) Reset() {
+	*x = S2C_ChatMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *S2C_ChatMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S2C_ChatMsg) ProtoMessage() {}
+
+func (x *S2C_ChatMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S2C_ChatMsg.ProtoReflect.Descriptor instead.
+func (*S2C_ChatMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *S2C_ChatMsg) GetErrorCode() ERRORCODE {
+	if x != nil && x.ErrorCode != nil {
+		return *x.ErrorCode
+	}
+	return ERRORCODE_ERR_Ok
+}
+
+func (x *S2C_ChatMsg) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *S2C_ChatMsg) GetSeat() int32 {
+	if x != nil && x.Seat != nil {
+		return *x.Seat
+	}
+	return 0
+}
+
+func (x *S2C_ChatMsg) GetScope() SCOPE {
+	if x != nil && x.Scope != nil {
+		return *x.Scope
+	}
+	return SCOPE_SCOPE_All
+}
+
+func (x *S2C_ChatMsg) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *S2C_ChatMsg) GetEmote() int32 {
+	if x != nil && x.Emote != nil {
+		return *x.Emote
+	}
+	return 0
+}
+
+func (x *S2C_ChatMsg) GetTimeStampMs() int64 {
+	if x != nil && x.TimeStampMs != nil {
+		return *x.TimeStampMs
+	}
+	return 0
+}
+
+//转发消息
+type C2S_RelayMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scope *SCOPE `protobuf:"varint,1,opt,name=scope,proto3,enum=pb.SCOPE,oneof" json:"scope,omitempty"` //接收范围
+	Seat  *int32 `protobuf:"varint,2,opt,name=seat,proto3,oneof" json:"seat,omitempty"`                 //SCOPE_Seat时的座位号
+	Type  *int32 `protobuf:"varint,3,opt,name=type,proto3,oneof" json:"type,omitempty"`                 //自定义消息类型
+	Data  []byte `protobuf:"bytes,4,opt,name=data,proto3,oneof" json:"data,omitempty"`                  //自定义数据
+}
+
+func (x *C2S_RelayMsg) Reset() {
+	*x = C2S_RelayMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *C2S_RelayMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*C2S_RelayMsg) ProtoMessage() {}
+
+func (x *C2S_RelayMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use C2S_RelayMsg.ProtoReflect.Descriptor instead.
+func (*C2S_RelayMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *C2S_RelayMsg) GetScope() SCOPE {
+	if x != nil && x.Scope != nil {
+		return *x.Scope
+	}
+	return SCOPE_SCOPE_All
+}
+
+func (x *C2S_RelayMsg) GetSeat() int32 {
+	if x != nil && x.Seat != nil {
+		return *x.Seat
+	}
+	return 0
+}
+
+func (x *C2S_RelayMsg) GetType() int32 {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return 0
+}
+
+func (x *C2S_RelayMsg) GetData() []byte {
+	if x != nil {
+		return x.Data
 	}
 	return nil
 }
 
-//广播帧消息
-type S2C_FrameMsg struct {
+//转发消息
+type S2C_RelayMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Frames []*FrameData `protobuf:"bytes,1,rep,name=frames,proto3" json:"frames,omitempty"` //帧数据
+	ErrorCode *ERRORCODE `protobuf:"varint,1,opt,name=errorCode,proto3,enum=pb.ERRORCODE,oneof" json:"errorCode,omitempty"` //不为ERR_Ok时表示自己发的消息被拒绝
+	Id        *uint64    `protobuf:"varint,2,opt,name=id,proto3,oneof" json:"id,omitempty"`                                 //发送者ID
+	Seat      *int32     `protobuf:"varint,3,opt,name=seat,proto3,oneof" json:"seat,omitempty"`                             //发送者座位号
+	Scope     *SCOPE     `protobuf:"varint,4,opt,name=scope,proto3,enum=pb.SCOPE,oneof" json:"scope,omitempty"`             //接收范围
+	Type      *int32     `protobuf:"varint,5,opt,name=type,proto3,oneof" json:"type,omitempty"`                             //自定义消息类型
+	Data      []byte     `protobuf:"bytes,6,opt,name=data,proto3,oneof" json:"data,omitempty"`                              //自定义数据
 }
 
-func (x *S2C_FrameMsg) Reset() {
-	*x = S2C_FrameMsg{}
+func (x *S2C_RelayMsg) Reset() {
+	*x = S2C_RelayMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_message_proto_msgTypes[9]
+		mi := &file_message_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *S2C_FrameMsg) String() string {
+func (x *S2C_RelayMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*S2C_FrameMsg) ProtoMessage() {}
+func (*S2C_RelayMsg) ProtoMessage() {}
 
-func (x *S2C_FrameMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_message_proto_msgTypes[9]
+func (x *S2C_RelayMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -733,44 +2416,79 @@ func (x *S2C_FrameMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use S2C_FrameMsg.ProtoReflect.Descriptor instead.
-func (*S2C_FrameMsg) Descriptor() ([]byte, []int) {
-	return file_message_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use S2C_RelayMsg.ProtoReflect.Descriptor instead.
+func (*S2C_RelayMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *S2C_FrameMsg) GetFrames() []*FrameData {
+func (x *S2C_RelayMsg) GetErrorCode() ERRORCODE {
+	if x != nil && x.ErrorCode != nil {
+		return *x.ErrorCode
+	}
+	return ERRORCODE_ERR_Ok
+}
+
+func (x *S2C_RelayMsg) GetId() uint64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *S2C_RelayMsg) GetSeat() int32 {
+	if x != nil && x.Seat != nil {
+		return *x.Seat
+	}
+	return 0
+}
+
+func (x *S2C_RelayMsg) GetScope() SCOPE {
+	if x != nil && x.Scope != nil {
+		return *x.Scope
+	}
+	return SCOPE_SCOPE_All
+}
+
+func (x *S2C_RelayMsg) GetType() int32 {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return 0
+}
+
+func (x *S2C_RelayMsg) GetData() []byte {
 	if x != nil {
-		return x.Frames
+		return x.Data
 	}
 	return nil
 }
 
-//结果消息
-type C2S_ResultMsg struct {
+//房间关闭
+type S2C_CloseMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	WinnerID *uint64 `protobuf:"varint,1,opt,name=winnerID,proto3,oneof" json:"winnerID,omitempty"` //胜利者ID
+	Reason *CLOSE `protobuf:"varint,1,opt,name=reason,proto3,enum=pb.CLOSE,oneof" json:"reason,omitempty"` //关闭原因
 }
 
-func (x *C2S_ResultMsg) Reset() {
-	*x = C2S_ResultMsg{}
+func (x *S2C_CloseMsg) Reset() {
+	*x = S2C_CloseMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_message_proto_msgTypes[10]
+		mi := &file_message_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *C2S_ResultMsg) String() string {
+func (x *S2C_CloseMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*C2S_ResultMsg) ProtoMessage() {}
+func (*S2C_CloseMsg) ProtoMessage() {}
 
-func (x *C2S_ResultMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_message_proto_msgTypes[10]
+func (x *S2C_CloseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -781,114 +2499,405 @@ func (x *C2S_ResultMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use C2S_ResultMsg.ProtoReflect.Descriptor instead.
-func (*C2S_ResultMsg) Descriptor() ([]byte, []int) {
-	return file_message_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use S2C_CloseMsg.ProtoReflect.Descriptor instead.
+func (*S2C_CloseMsg) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *C2S_ResultMsg) GetWinnerID() uint64 {
-	if x != nil && x.WinnerID != nil {
-		return *x.WinnerID
+func (x *S2C_CloseMsg) GetReason() CLOSE {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
 	}
-	return 0
+	return CLOSE_CLOSE_Over
 }
 
 var File_message_proto protoreflect.FileDescriptor
 
 var file_message_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x02, 0x70, 0x62, 0x22, 0x91, 0x01, 0x0a, 0x0e, 0x43, 0x32, 0x53, 0x5f, 0x43, 0x6f, 0x6e, 0x6e,
+	0x02, 0x70, 0x62, 0x22, 0xc2, 0x01, 0x0a, 0x0e, 0x43, 0x32, 0x53, 0x5f, 0x43, 0x6f, 0x6e, 0x6e,
 	0x65, 0x63, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
 	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79,
 	0x65, 0x72, 0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x62, 0x61, 0x74, 0x74, 0x6c,
 	0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x08, 0x62, 0x61, 0x74,
 	0x74, 0x6c, 0x65, 0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65,
 	0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
-	0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x44,
-	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x49, 0x44, 0x42, 0x08, 0x0a,
-	0x06, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x50, 0x0a, 0x0e, 0x53, 0x32, 0x43, 0x5f, 0x43,
-	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x30, 0x0a, 0x09, 0x65, 0x72, 0x72,
-	0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x70,
-	0x62, 0x2e, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x43, 0x4f, 0x44, 0x45, 0x48, 0x00, 0x52, 0x09, 0x65,
-	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
-	0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0xa5, 0x01, 0x0a, 0x0f, 0x53, 0x32,
-	0x43, 0x5f, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x6d, 0x4d, 0x73, 0x67, 0x12, 0x23, 0x0a,
-	0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69, 0x64, 0x88,
-	0x01, 0x01, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x04, 0x52, 0x06, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x72,
-	0x6f, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52, 0x04, 0x70, 0x72, 0x6f, 0x73, 0x12, 0x23,
-	0x0a, 0x0a, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x53, 0x65, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x05, 0x48, 0x01, 0x52, 0x0a, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x53, 0x65, 0x65, 0x64,
-	0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74,
-	0x69, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x53, 0x65, 0x65,
-	0x64, 0x22, 0x3f, 0x0a, 0x0c, 0x53, 0x32, 0x43, 0x5f, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4d, 0x73,
-	0x67, 0x12, 0x21, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d,
-	0x70, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61,
-	0x6d, 0x70, 0x22, 0x30, 0x0a, 0x0f, 0x43, 0x32, 0x53, 0x5f, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
-	0x73, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x15, 0x0a, 0x03, 0x70, 0x72, 0x6f, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x48, 0x00, 0x52, 0x03, 0x70, 0x72, 0x6f, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04,
-	0x5f, 0x70, 0x72, 0x6f, 0x22, 0x4c, 0x0a, 0x0f, 0x53, 0x32, 0x43, 0x5f, 0x50, 0x72, 0x6f, 0x67,
-	0x72, 0x65, 0x73, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03,
-	0x70, 0x72, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x03, 0x70, 0x72, 0x6f,
-	0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x70,
-	0x72, 0x6f, 0x22, 0x8a, 0x01, 0x0a, 0x0c, 0x43, 0x32, 0x53, 0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74,
-	0x4d, 0x73, 0x67, 0x12, 0x15, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
-	0x48, 0x00, 0x52, 0x03, 0x73, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x11, 0x0a, 0x01, 0x78, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x01, 0x78, 0x88, 0x01, 0x01, 0x12, 0x11, 0x0a,
-	0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x01, 0x79, 0x88, 0x01, 0x01,
-	0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0d, 0x48, 0x03, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x88, 0x01, 0x01, 0x42,
-	0x06, 0x0a, 0x04, 0x5f, 0x73, 0x69, 0x64, 0x42, 0x04, 0x0a, 0x02, 0x5f, 0x78, 0x42, 0x04, 0x0a,
-	0x02, 0x5f, 0x79, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x22,
-	0xac, 0x01, 0x0a, 0x09, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x13, 0x0a,
+	0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x09, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61,
+	0x74, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x49, 0x44, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x49, 0x44,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73,
+	0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x50, 0x0a, 0x0e, 0x53, 0x32, 0x43, 0x5f,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x30, 0x0a, 0x09, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e,
+	0x70, 0x62, 0x2e, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x43, 0x4f, 0x44, 0x45, 0x48, 0x00, 0x52, 0x09,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0xa5, 0x01, 0x0a, 0x0f, 0x53,
+	0x32, 0x43, 0x5f, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x6d, 0x4d, 0x73, 0x67, 0x12, 0x23,
+	0x0a, 0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69, 0x64,
+	0x88, 0x01, 0x01, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x72, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52, 0x04, 0x70, 0x72, 0x6f, 0x73, 0x12,
+	0x23, 0x0a, 0x0a, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x53, 0x65, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0a, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x53, 0x65, 0x65,
+	0x64, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61,
+	0x74, 0x69, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x53, 0x65,
+	0x65, 0x64, 0x22, 0x8d, 0x02, 0x0a, 0x0c, 0x53, 0x32, 0x43, 0x5f, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x4d, 0x73, 0x67, 0x12, 0x21, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74,
+	0x61, 0x6d, 0x70, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74,
+	0x61, 0x6d, 0x70, 0x4d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x0b, 0x74,
+	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x08, 0x2e, 0x70, 0x62,
+	0x2e, 0x4d, 0x4f, 0x44, 0x45, 0x48, 0x02, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x29, 0x0a, 0x0d, 0x74, 0x75, 0x72, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x0d, 0x74, 0x75, 0x72, 0x6e, 0x54,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x74,
+	0x69, 0x63, 0x6b, 0x52, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52,
+	0x08, 0x74, 0x69, 0x63, 0x6b, 0x52, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6d,
+	0x6f, 0x64, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x74, 0x75, 0x72, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x4d, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x52, 0x61,
+	0x74, 0x65, 0x22, 0xac, 0x02, 0x0a, 0x10, 0x53, 0x32, 0x43, 0x5f, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x64, 0x6f, 0x77, 0x6e, 0x4d, 0x73, 0x67, 0x12, 0x2d, 0x0a, 0x0f, 0x72, 0x65, 0x61, 0x64, 0x79,
+	0x44, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x4d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x48, 0x00, 0x52, 0x0f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x44, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e,
+	0x65, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x4d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x0b, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a,
+	0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x05,
+	0x72, 0x65, 0x61, 0x64, 0x79, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x61, 0x64, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x61, 0x64,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x68, 0x6f, 0x73, 0x74, 0x49, 0x44, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x48, 0x05, 0x52, 0x06, 0x68, 0x6f, 0x73, 0x74, 0x49, 0x44, 0x88, 0x01,
+	0x01, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x44, 0x65, 0x61, 0x64, 0x6c,
+	0x69, 0x6e, 0x65, 0x4d, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x4d, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x42,
+	0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x69,
+	0x6e, 0x52, 0x65, 0x61, 0x64, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x49,
+	0x44, 0x22, 0xd1, 0x01, 0x0a, 0x0b, 0x43, 0x32, 0x53, 0x5f, 0x50, 0x69, 0x6e, 0x67, 0x4d, 0x73,
+	0x67, 0x12, 0x23, 0x0a, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x65, 0x63, 0x68, 0x6f, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x08, 0x65, 0x63, 0x68, 0x6f,
+	0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x68, 0x6f, 0x6c, 0x64, 0x54,
+	0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x02, 0x52, 0x08, 0x68, 0x6f, 0x6c,
+	0x64, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x61, 0x63, 0x6b, 0x46,
+	0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x0a,
+	0x61, 0x63, 0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a,
+	0x0b, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x0b, 0x0a, 0x09,
+	0x5f, 0x65, 0x63, 0x68, 0x6f, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x68, 0x6f,
+	0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x61, 0x63, 0x6b, 0x46, 0x72,
+	0x61, 0x6d, 0x65, 0x49, 0x44, 0x22, 0xe0, 0x01, 0x0a, 0x0b, 0x53, 0x32, 0x43, 0x5f, 0x50, 0x69,
+	0x6e, 0x67, 0x4d, 0x73, 0x67, 0x12, 0x23, 0x0a, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x52, 0x65, 0x63, 0x76, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x01, 0x52, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x65, 0x63, 0x76,
+	0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x02, 0x52, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x72, 0x74, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x03, 0x52, 0x03, 0x72, 0x74, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x65, 0x63, 0x76, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x11, 0x0a,
+	0x0f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x42, 0x06, 0x0a, 0x04, 0x5f, 0x72, 0x74, 0x74, 0x22, 0x30, 0x0a, 0x0f, 0x43, 0x32, 0x53, 0x5f,
+	0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x15, 0x0a, 0x03, 0x70,
+	0x72, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x03, 0x70, 0x72, 0x6f, 0x88,
+	0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x70, 0x72, 0x6f, 0x22, 0x4c, 0x0a, 0x0f, 0x53, 0x32,
+	0x43, 0x5f, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x13, 0x0a,
 	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x88,
-	0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48,
-	0x01, 0x52, 0x03, 0x73, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x11, 0x0a, 0x01, 0x78, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x01, 0x78, 0x88, 0x01, 0x01, 0x12, 0x11, 0x0a, 0x01,
-	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x01, 0x79, 0x88, 0x01, 0x01, 0x12,
-	0x23, 0x0a, 0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69, 0x64, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x05, 0x48, 0x04, 0x52, 0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69,
-	0x64, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x5f,
-	0x73, 0x69, 0x64, 0x42, 0x04, 0x0a, 0x02, 0x5f, 0x78, 0x42, 0x04, 0x0a, 0x02, 0x5f, 0x79, 0x42,
-	0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69, 0x64, 0x22, 0x5b,
-	0x0a, 0x09, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x07, 0x66,
-	0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x07,
-	0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x05, 0x69, 0x6e,
-	0x70, 0x75, 0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x49,
-	0x6e, 0x70, 0x75, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x42,
-	0x0a, 0x0a, 0x08, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x22, 0x35, 0x0a, 0x0c, 0x53,
-	0x32, 0x43, 0x5f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x25, 0x0a, 0x06, 0x66,
-	0x72, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62,
-	0x2e, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x06, 0x66, 0x72, 0x61, 0x6d,
-	0x65, 0x73, 0x22, 0x3d, 0x0a, 0x0d, 0x43, 0x32, 0x53, 0x5f, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x4d, 0x73, 0x67, 0x12, 0x1f, 0x0a, 0x08, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x08, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x49,
-	0x44, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x49,
-	0x44, 0x2a, 0xc4, 0x01, 0x0a, 0x02, 0x49, 0x44, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f,
-	0x42, 0x45, 0x47, 0x49, 0x4e, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x53, 0x47, 0x5f, 0x43,
-	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x4d, 0x53, 0x47, 0x5f,
-	0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x4d,
-	0x53, 0x47, 0x5f, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x6d, 0x10, 0x0a, 0x12, 0x10, 0x0a,
-	0x0c, 0x4d, 0x53, 0x47, 0x5f, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x10, 0x14, 0x12,
-	0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x52, 0x65, 0x61, 0x64, 0x79, 0x10, 0x1e, 0x12, 0x0d,
-	0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x53, 0x74, 0x61, 0x72, 0x74, 0x10, 0x28, 0x12, 0x0d, 0x0a,
-	0x09, 0x4d, 0x53, 0x47, 0x5f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x10, 0x32, 0x12, 0x0d, 0x0a, 0x09,
-	0x4d, 0x53, 0x47, 0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x10, 0x3c, 0x12, 0x0e, 0x0a, 0x0a, 0x4d,
-	0x53, 0x47, 0x5f, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x10, 0x46, 0x12, 0x0d, 0x0a, 0x09, 0x4d,
-	0x53, 0x47, 0x5f, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x10, 0x64, 0x12, 0x0c, 0x0a, 0x07, 0x4d, 0x53,
-	0x47, 0x5f, 0x45, 0x4e, 0x44, 0x10, 0xff, 0x01, 0x2a, 0x5b, 0x0a, 0x09, 0x45, 0x52, 0x52, 0x4f,
-	0x52, 0x43, 0x4f, 0x44, 0x45, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x52, 0x52, 0x5f, 0x4f, 0x6b, 0x10,
-	0x00, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x52, 0x52, 0x5f, 0x4e, 0x6f, 0x50, 0x6c, 0x61, 0x79, 0x65,
-	0x72, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x45, 0x52, 0x52, 0x5f, 0x4e, 0x6f, 0x52, 0x6f, 0x6f,
-	0x6d, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x52, 0x52, 0x5f, 0x52, 0x6f, 0x6f, 0x6d, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x45, 0x52, 0x52, 0x5f, 0x54, 0x6f,
-	0x6b, 0x65, 0x6e, 0x10, 0x04, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x79, 0x65, 0x62, 0x79, 0x65, 0x62, 0x72, 0x75, 0x63, 0x65, 0x2f,
-	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x74, 0x65, 0x70, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x70,
-	0x62, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x70, 0x72, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x01, 0x52, 0x03, 0x70, 0x72, 0x6f, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64,
+	0x42, 0x06, 0x0a, 0x04, 0x5f, 0x70, 0x72, 0x6f, 0x22, 0xd5, 0x01, 0x0a, 0x0c, 0x43, 0x32, 0x53,
+	0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x15, 0x0a, 0x03, 0x73, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x03, 0x73, 0x69, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x11, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x01, 0x78,
+	0x88, 0x01, 0x01, 0x12, 0x11, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02,
+	0x52, 0x01, 0x79, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49,
+	0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x07,
+	0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x70, 0x62, 0x2e, 0x43, 0x32, 0x53, 0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4d, 0x73, 0x67, 0x52,
+	0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x73, 0x69, 0x64,
+	0x42, 0x04, 0x0a, 0x02, 0x5f, 0x78, 0x42, 0x04, 0x0a, 0x02, 0x5f, 0x79, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x73, 0x65, 0x71,
+	0x22, 0xb3, 0x01, 0x0a, 0x12, 0x53, 0x32, 0x43, 0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x65,
+	0x6a, 0x65, 0x63, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d,
+	0x65, 0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x63, 0x75, 0x72, 0x46, 0x72, 0x61,
+	0x6d, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x0a, 0x63, 0x75,
+	0x72, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x09, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d,
+	0x2e, 0x70, 0x62, 0x2e, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x43, 0x4f, 0x44, 0x45, 0x48, 0x02, 0x52,
+	0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a,
+	0x08, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x75,
+	0x72, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0xdc, 0x01, 0x0a, 0x09, 0x49, 0x6e, 0x70, 0x75, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x73, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x03, 0x73, 0x69, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x11, 0x0a, 0x01, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x01, 0x78,
+	0x88, 0x01, 0x01, 0x12, 0x11, 0x0a, 0x01, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03,
+	0x52, 0x01, 0x79, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65,
+	0x61, 0x74, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x04, 0x52, 0x0a, 0x72, 0x6f,
+	0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x05, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e,
+	0x45, 0x56, 0x45, 0x4e, 0x54, 0x48, 0x05, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x88, 0x01,
+	0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x73, 0x69, 0x64,
+	0x42, 0x04, 0x0a, 0x02, 0x5f, 0x78, 0x42, 0x04, 0x0a, 0x02, 0x5f, 0x79, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x65, 0x61, 0x74, 0x69, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x5f,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x5b, 0x0a, 0x09, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x88, 0x01,
+	0x01, 0x12, 0x23, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x49, 0x44, 0x22, 0x9d, 0x01, 0x0a, 0x0c, 0x53, 0x32, 0x43, 0x5f, 0x46, 0x72, 0x61, 0x6d, 0x65,
+	0x4d, 0x73, 0x67, 0x12, 0x25, 0x0a, 0x06, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x06, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0b, 0x66, 0x72,
+	0x6f, 0x6d, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48,
+	0x00, 0x52, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x88, 0x01,
+	0x01, 0x12, 0x21, 0x0a, 0x09, 0x74, 0x6f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x09, 0x74, 0x6f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x49,
+	0x44, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x46, 0x72, 0x61,
+	0x6d, 0x65, 0x49, 0x44, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x74, 0x6f, 0x46, 0x72, 0x61, 0x6d, 0x65,
+	0x49, 0x44, 0x22, 0x80, 0x02, 0x0a, 0x0c, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x48,
+	0x00, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x88, 0x01,
+	0x01, 0x12, 0x17, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x02, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x05, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x72, 0x61,
+	0x6e, 0x6b, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x42, 0x08, 0x0a, 0x06, 0x5f,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x5a, 0x0a, 0x0b, 0x43, 0x32, 0x53, 0x5f, 0x48, 0x61, 0x73,
+	0x68, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44,
+	0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x48, 0x01, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x22, 0x8e, 0x01, 0x0a, 0x0b, 0x53, 0x32, 0x43, 0x5f, 0x48, 0x61, 0x73, 0x68, 0x4d, 0x73,
+	0x67, 0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x00, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x88, 0x01, 0x01,
+	0x12, 0x17, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01,
+	0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x48, 0x02, 0x52,
+	0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x42, 0x0a,
+	0x0a, 0x08, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x48, 0x61,
+	0x73, 0x68, 0x22, 0xcd, 0x01, 0x0a, 0x0f, 0x43, 0x32, 0x53, 0x5f, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49,
+	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x12, 0x19,
+	0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52,
+	0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x04, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01, 0x42,
+	0x0a, 0x0a, 0x08, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x64, 0x61,
+	0x74, 0x61, 0x22, 0xcd, 0x01, 0x0a, 0x0f, 0x53, 0x32, 0x43, 0x5f, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49,
+	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x12, 0x19,
+	0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52,
+	0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x04, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01, 0x42,
+	0x0a, 0x0a, 0x08, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x64, 0x61,
+	0x74, 0x61, 0x22, 0x51, 0x0a, 0x0b, 0x43, 0x32, 0x53, 0x5f, 0x4b, 0x69, 0x63, 0x6b, 0x4d, 0x73,
+	0x67, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52,
+	0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0xa7, 0x01, 0x0a, 0x0b, 0x53, 0x32, 0x43, 0x5f, 0x4b, 0x69,
+	0x63, 0x6b, 0x4d, 0x73, 0x67, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x13, 0x0a, 0x02, 0x62, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x02, 0x62, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x1b, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x02, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c,
+	0x72, 0x65, 0x6a, 0x6f, 0x69, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x03, 0x52, 0x0c, 0x72, 0x65, 0x6a, 0x6f, 0x69, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x4d, 0x73, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x05, 0x0a, 0x03,
+	0x5f, 0x62, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x42, 0x0f,
+	0x0a, 0x0d, 0x5f, 0x72, 0x65, 0x6a, 0x6f, 0x69, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x22,
+	0x8b, 0x01, 0x0a, 0x0d, 0x43, 0x32, 0x53, 0x5f, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x73,
+	0x67, 0x12, 0x1f, 0x0a, 0x08, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x08, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x88,
+	0x01, 0x01, 0x12, 0x2a, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x17,
+	0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x01, 0x52, 0x04,
+	0x62, 0x6c, 0x6f, 0x62, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x77, 0x69, 0x6e, 0x6e,
+	0x65, 0x72, 0x49, 0x44, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x22, 0x7a, 0x0a,
+	0x0f, 0x53, 0x32, 0x43, 0x5f, 0x4e, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x73, 0x67,
+	0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x02,
+	0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x64, 0x65, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x08, 0x64, 0x65, 0x67, 0x72, 0x61,
+	0x64, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x72, 0x74, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x03, 0x72, 0x74, 0x74, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a,
+	0x03, 0x5f, 0x69, 0x64, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x64, 0x65, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x64, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x72, 0x74, 0x74, 0x22, 0x33, 0x0a, 0x0c, 0x43, 0x32, 0x53,
+	0x5f, 0x50, 0x61, 0x75, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x19, 0x0a, 0x05, 0x70, 0x61, 0x75,
+	0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x05, 0x70, 0x61, 0x75, 0x73,
+	0x65, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x70, 0x61, 0x75, 0x73, 0x65, 0x22, 0xd3,
+	0x01, 0x0a, 0x0c, 0x53, 0x32, 0x43, 0x5f, 0x50, 0x61, 0x75, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12,
+	0x1b, 0x0a, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x00, 0x52, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x13, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x1d, 0x0a, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x02, 0x52, 0x07, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x88, 0x01, 0x01,
+	0x12, 0x19, 0x0a, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48,
+	0x03, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x6e,
+	0x65, 0x65, 0x64, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04,
+	0x52, 0x09, 0x6e, 0x65, 0x65, 0x64, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x44, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6e, 0x65, 0x65, 0x64, 0x56,
+	0x6f, 0x74, 0x65, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x0b, 0x43, 0x32, 0x53, 0x5f, 0x43, 0x68, 0x61,
+	0x74, 0x4d, 0x73, 0x67, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x48, 0x00,
+	0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x02, 0x52, 0x05, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x88, 0x01, 0x01, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x65, 0x78,
+	0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x22, 0xb9, 0x02, 0x0a, 0x0b,
+	0x53, 0x32, 0x43, 0x5f, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x30, 0x0a, 0x09, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d,
+	0x2e, 0x70, 0x62, 0x2e, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x43, 0x4f, 0x44, 0x45, 0x48, 0x00, 0x52,
+	0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x13, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x02, 0x69, 0x64, 0x88,
+	0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x65, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x02, 0x52, 0x04, 0x73, 0x65, 0x61, 0x74, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x05, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e,
+	0x53, 0x43, 0x4f, 0x50, 0x45, 0x48, 0x03, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x17, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x04, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x48, 0x05, 0x52, 0x05, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61,
+	0x6d, 0x70, 0x4d, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x48, 0x06, 0x52, 0x0b, 0x74, 0x69,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69,
+	0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x65, 0x61, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x42, 0x08, 0x0a,
+	0x06, 0x5f, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x53, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x73, 0x22, 0xa4, 0x01, 0x0a, 0x0c, 0x43, 0x32, 0x53, 0x5f,
+	0x52, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73, 0x67, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x43, 0x4f,
+	0x50, 0x45, 0x48, 0x00, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x17,
+	0x0a, 0x04, 0x73, 0x65, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x04,
+	0x73, 0x65, 0x61, 0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x17, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x03,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x63,
+	0x6f, 0x70, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x65, 0x61, 0x74, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x22, 0x80,
+	0x02, 0x0a, 0x0c, 0x53, 0x32, 0x43, 0x5f, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73, 0x67, 0x12,
+	0x30, 0x0a, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x43, 0x4f, 0x44,
+	0x45, 0x48, 0x00, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52,
+	0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x65, 0x61, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x04, 0x73, 0x65, 0x61, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x24, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09,
+	0x2e, 0x70, 0x62, 0x2e, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x48, 0x03, 0x52, 0x05, 0x73, 0x63, 0x6f,
+	0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x04, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x17,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x05, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x43, 0x6f, 0x64, 0x65, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x73, 0x65, 0x61, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x22, 0x41, 0x0a, 0x0c, 0x53, 0x32, 0x43, 0x5f, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x4d, 0x73,
+	0x67, 0x12, 0x26, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x48, 0x00, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x2a, 0x88, 0x03, 0x0a, 0x02, 0x49, 0x44, 0x12, 0x0d, 0x0a, 0x09, 0x4d,
+	0x53, 0x47, 0x5f, 0x42, 0x45, 0x47, 0x49, 0x4e, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x53,
+	0x47, 0x5f, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x4d,
+	0x53, 0x47, 0x5f, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x10, 0x02, 0x12, 0x0c,
+	0x0a, 0x08, 0x4d, 0x53, 0x47, 0x5f, 0x50, 0x69, 0x6e, 0x67, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c,
+	0x4d, 0x53, 0x47, 0x5f, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x6d, 0x10, 0x0a, 0x12, 0x10,
+	0x0a, 0x0c, 0x4d, 0x53, 0x47, 0x5f, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x10, 0x14,
+	0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x52, 0x65, 0x61, 0x64, 0x79, 0x10, 0x1e, 0x12,
+	0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x53, 0x74, 0x61, 0x72, 0x74, 0x10, 0x28, 0x12, 0x11,
+	0x0a, 0x0d, 0x4d, 0x53, 0x47, 0x5f, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x10,
+	0x29, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x10, 0x32,
+	0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x10, 0x3c, 0x12,
+	0x13, 0x0a, 0x0f, 0x4d, 0x53, 0x47, 0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x65, 0x6a, 0x65,
+	0x63, 0x74, 0x10, 0x3d, 0x12, 0x0c, 0x0a, 0x08, 0x4d, 0x53, 0x47, 0x5f, 0x48, 0x61, 0x73, 0x68,
+	0x10, 0x3e, 0x12, 0x10, 0x0a, 0x0c, 0x4d, 0x53, 0x47, 0x5f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x10, 0x3f, 0x12, 0x0e, 0x0a, 0x0a, 0x4d, 0x53, 0x47, 0x5f, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x10, 0x46, 0x12, 0x11, 0x0a, 0x0d, 0x4d, 0x53, 0x47, 0x5f, 0x53, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x10, 0x47, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x4c,
+	0x65, 0x61, 0x76, 0x65, 0x10, 0x48, 0x12, 0x0c, 0x0a, 0x08, 0x4d, 0x53, 0x47, 0x5f, 0x4b, 0x69,
+	0x63, 0x6b, 0x10, 0x49, 0x12, 0x10, 0x0a, 0x0c, 0x4d, 0x53, 0x47, 0x5f, 0x4e, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x10, 0x50, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x50, 0x61,
+	0x75, 0x73, 0x65, 0x10, 0x5a, 0x12, 0x0c, 0x0a, 0x08, 0x4d, 0x53, 0x47, 0x5f, 0x43, 0x68, 0x61,
+	0x74, 0x10, 0x6e, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x52, 0x65, 0x6c, 0x61, 0x79,
+	0x10, 0x78, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x53, 0x47, 0x5f, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x10,
+	0x64, 0x12, 0x0c, 0x0a, 0x07, 0x4d, 0x53, 0x47, 0x5f, 0x45, 0x4e, 0x44, 0x10, 0xff, 0x01, 0x2a,
+	0xed, 0x01, 0x0a, 0x09, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x43, 0x4f, 0x44, 0x45, 0x12, 0x0a, 0x0a,
+	0x06, 0x45, 0x52, 0x52, 0x5f, 0x4f, 0x6b, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x52, 0x52,
+	0x5f, 0x4e, 0x6f, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x45,
+	0x52, 0x52, 0x5f, 0x4e, 0x6f, 0x52, 0x6f, 0x6f, 0x6d, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x45,
+	0x52, 0x52, 0x5f, 0x52, 0x6f, 0x6f, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x10, 0x03, 0x12, 0x0d,
+	0x0a, 0x09, 0x45, 0x52, 0x52, 0x5f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x10, 0x04, 0x12, 0x11, 0x0a,
+	0x0d, 0x45, 0x52, 0x52, 0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4c, 0x61, 0x74, 0x65, 0x10, 0x05,
+	0x12, 0x12, 0x0a, 0x0e, 0x45, 0x52, 0x52, 0x5f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x41, 0x68, 0x65,
+	0x61, 0x64, 0x10, 0x06, 0x12, 0x15, 0x0a, 0x11, 0x45, 0x52, 0x52, 0x5f, 0x53, 0x70, 0x65, 0x63,
+	0x74, 0x61, 0x74, 0x6f, 0x72, 0x46, 0x75, 0x6c, 0x6c, 0x10, 0x07, 0x12, 0x0c, 0x0a, 0x08, 0x45,
+	0x52, 0x52, 0x5f, 0x4c, 0x65, 0x66, 0x74, 0x10, 0x08, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x52, 0x52,
+	0x5f, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x10, 0x09, 0x12, 0x0f, 0x0a, 0x0b,
+	0x45, 0x52, 0x52, 0x5f, 0x54, 0x6f, 0x6f, 0x4c, 0x6f, 0x6e, 0x67, 0x10, 0x0a, 0x12, 0x10, 0x0a,
+	0x0c, 0x45, 0x52, 0x52, 0x5f, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x65, 0x64, 0x10, 0x0b, 0x12,
+	0x0e, 0x0a, 0x0a, 0x45, 0x52, 0x52, 0x5f, 0x4b, 0x69, 0x63, 0x6b, 0x65, 0x64, 0x10, 0x0c, 0x2a,
+	0x48, 0x0a, 0x05, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x43, 0x4f, 0x50,
+	0x45, 0x5f, 0x41, 0x6c, 0x6c, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x43, 0x4f, 0x50, 0x45,
+	0x5f, 0x54, 0x65, 0x61, 0x6d, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x43, 0x4f, 0x50, 0x45,
+	0x5f, 0x4f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x43, 0x4f,
+	0x50, 0x45, 0x5f, 0x53, 0x65, 0x61, 0x74, 0x10, 0x03, 0x2a, 0x91, 0x01, 0x0a, 0x05, 0x45, 0x56,
+	0x45, 0x4e, 0x54, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x56, 0x54, 0x5f, 0x4e, 0x6f, 0x6e, 0x65, 0x10,
+	0x00, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x56, 0x54, 0x5f, 0x4a, 0x6f, 0x69, 0x6e, 0x10, 0x01, 0x12,
+	0x0e, 0x0a, 0x0a, 0x45, 0x56, 0x54, 0x5f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x10, 0x02, 0x12,
+	0x12, 0x0a, 0x0e, 0x45, 0x56, 0x54, 0x5f, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x56, 0x54, 0x5f, 0x52, 0x65, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x56, 0x54, 0x5f, 0x53, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x10, 0x05, 0x12, 0x0d, 0x0a, 0x09, 0x45, 0x56, 0x54,
+	0x5f, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x10, 0x06, 0x12, 0x13, 0x0a, 0x0f, 0x45, 0x56, 0x54, 0x5f,
+	0x54, 0x75, 0x72, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x10, 0x07, 0x2a, 0x25, 0x0a,
+	0x04, 0x4d, 0x4f, 0x44, 0x45, 0x12, 0x0e, 0x0a, 0x0a, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x65,
+	0x6c, 0x61, 0x79, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x54, 0x75,
+	0x72, 0x6e, 0x10, 0x01, 0x2a, 0x3b, 0x0a, 0x05, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x12, 0x0e, 0x0a,
+	0x0a, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x5f, 0x4f, 0x76, 0x65, 0x72, 0x10, 0x00, 0x12, 0x11, 0x0a,
+	0x0d, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x5f, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x10, 0x01,
+	0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x5f, 0x43, 0x72, 0x61, 0x73, 0x68, 0x10,
+	0x02, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x62, 0x79, 0x65, 0x62, 0x79, 0x65, 0x62, 0x72, 0x75, 0x63, 0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x74, 0x65, 0x70, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x70, 0x62, 0x3b, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -903,32 +2912,69 @@ func file_message_proto_rawDescGZIP() []byte {
 	return file_message_proto_rawDescData
 }
 
-var file_message_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_message_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_message_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_message_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_message_proto_goTypes = []interface{}{
-	(ID)(0),                 // 0: pb.ID
-	(ERRORCODE)(0),          // 1: pb.ERRORCODE
-	(*C2S_ConnectMsg)(nil),  // 2: pb.C2S_ConnectMsg
-	(*S2C_ConnectMsg)(nil),  // 3: pb.S2C_ConnectMsg
-	(*S2C_JoinRoomMsg)(nil), // 4: pb.S2C_JoinRoomMsg
-	(*S2C_StartMsg)(nil),    // 5: pb.S2C_StartMsg
-	(*C2S_ProgressMsg)(nil), // 6: pb.C2S_ProgressMsg
-	(*S2C_ProgressMsg)(nil), // 7: pb.S2C_ProgressMsg
-	(*C2S_InputMsg)(nil),    // 8: pb.C2S_InputMsg
-	(*InputData)(nil),       // 9: pb.InputData
-	(*FrameData)(nil),       // 10: pb.FrameData
-	(*S2C_FrameMsg)(nil),    // 11: pb.S2C_FrameMsg
-	(*C2S_ResultMsg)(nil),   // 12: pb.C2S_ResultMsg
+	(ID)(0),                    // 0: pb.ID
+	(ERRORCODE)(0),             // 1: pb.ERRORCODE
+	(SCOPE)(0),                 // 2: pb.SCOPE
+	(EVENT)(0),                 // 3: pb.EVENT
+	(MODE)(0),                  // 4: pb.MODE
+	(CLOSE)(0),                 // 5: pb.CLOSE
+	(*C2S_ConnectMsg)(nil),     // 6: pb.C2S_ConnectMsg
+	(*S2C_ConnectMsg)(nil),     // 7: pb.S2C_ConnectMsg
+	(*S2C_JoinRoomMsg)(nil),    // 8: pb.S2C_JoinRoomMsg
+	(*S2C_StartMsg)(nil),       // 9: pb.S2C_StartMsg
+	(*S2C_CountdownMsg)(nil),   // 10: pb.S2C_CountdownMsg
+	(*C2S_PingMsg)(nil),        // 11: pb.C2S_PingMsg
+	(*S2C_PingMsg)(nil),        // 12: pb.S2C_PingMsg
+	(*C2S_ProgressMsg)(nil),    // 13: pb.C2S_ProgressMsg
+	(*S2C_ProgressMsg)(nil),    // 14: pb.S2C_ProgressMsg
+	(*C2S_InputMsg)(nil),       // 15: pb.C2S_InputMsg
+	(*S2C_InputRejectMsg)(nil), // 16: pb.S2C_InputRejectMsg
+	(*InputData)(nil),          // 17: pb.InputData
+	(*FrameData)(nil),          // 18: pb.FrameData
+	(*S2C_FrameMsg)(nil),       // 19: pb.S2C_FrameMsg
+	(*PlayerResult)(nil),       // 20: pb.PlayerResult
+	(*C2S_HashMsg)(nil),        // 21: pb.C2S_HashMsg
+	(*S2C_HashMsg)(nil),        // 22: pb.S2C_HashMsg
+	(*C2S_SnapshotMsg)(nil),    // 23: pb.C2S_SnapshotMsg
+	(*S2C_SnapshotMsg)(nil),    // 24: pb.S2C_SnapshotMsg
+	(*C2S_KickMsg)(nil),        // 25: pb.C2S_KickMsg
+	(*S2C_KickMsg)(nil),        // 26: pb.S2C_KickMsg
+	(*C2S_ResultMsg)(nil),      // 27: pb.C2S_ResultMsg
+	(*S2C_NetStateMsg)(nil),    // 28: pb.S2C_NetStateMsg
+	(*C2S_PauseMsg)(nil),       // 29: pb.C2S_PauseMsg
+	(*S2C_PauseMsg)(nil),       // 30: pb.S2C_PauseMsg
+	(*C2S_ChatMsg)(nil),        // 31: pb.C2S_ChatMsg
+	(*S2C_ChatMsg)(nil),        // 32: pb.S2C_ChatMsg
+	(*C2S_RelayMsg)(nil),       // 33: pb.C2S_RelayMsg
+	(*S2C_RelayMsg)(nil),       // 34: pb.S2C_RelayMsg
+	(*S2C_CloseMsg)(nil),       // 35: pb.S2C_CloseMsg
+	nil,                        // 36: pb.PlayerResult.StatsEntry
 }
 var file_message_proto_depIdxs = []int32{
 	1,  // 0: pb.S2C_ConnectMsg.errorCode:type_name -> pb.ERRORCODE
-	9,  // 1: pb.FrameData.input:type_name -> pb.InputData
-	10, // 2: pb.S2C_FrameMsg.frames:type_name -> pb.FrameData
-	3,  // [3:3] is the sub-list for method output_type
-	3,  // [3:3] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	4,  // 1: pb.S2C_StartMsg.mode:type_name -> pb.MODE
+	15, // 2: pb.C2S_InputMsg.history:type_name -> pb.C2S_InputMsg
+	1,  // 3: pb.S2C_InputRejectMsg.errorCode:type_name -> pb.ERRORCODE
+	3,  // 4: pb.InputData.event:type_name -> pb.EVENT
+	17, // 5: pb.FrameData.input:type_name -> pb.InputData
+	18, // 6: pb.S2C_FrameMsg.frames:type_name -> pb.FrameData
+	36, // 7: pb.PlayerResult.stats:type_name -> pb.PlayerResult.StatsEntry
+	20, // 8: pb.C2S_ResultMsg.players:type_name -> pb.PlayerResult
+	2,  // 9: pb.C2S_ChatMsg.scope:type_name -> pb.SCOPE
+	1,  // 10: pb.S2C_ChatMsg.errorCode:type_name -> pb.ERRORCODE
+	2,  // 11: pb.S2C_ChatMsg.scope:type_name -> pb.SCOPE
+	2,  // 12: pb.C2S_RelayMsg.scope:type_name -> pb.SCOPE
+	1,  // 13: pb.S2C_RelayMsg.errorCode:type_name -> pb.ERRORCODE
+	2,  // 14: pb.S2C_RelayMsg.scope:type_name -> pb.SCOPE
+	5,  // 15: pb.S2C_CloseMsg.reason:type_name -> pb.CLOSE
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_message_proto_init() }
@@ -986,7 +3032,7 @@ func file_message_proto_init() {
 			}
 		}
 		file_message_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*C2S_ProgressMsg); i {
+			switch v := v.(*S2C_CountdownMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -998,7 +3044,7 @@ func file_message_proto_init() {
 			}
 		}
 		file_message_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*S2C_ProgressMsg); i {
+			switch v := v.(*C2S_PingMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1010,7 +3056,7 @@ func file_message_proto_init() {
 			}
 		}
 		file_message_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*C2S_InputMsg); i {
+			switch v := v.(*S2C_PingMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1022,7 +3068,7 @@ func file_message_proto_init() {
 			}
 		}
 		file_message_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InputData); i {
+			switch v := v.(*C2S_ProgressMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1034,7 +3080,7 @@ func file_message_proto_init() {
 			}
 		}
 		file_message_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FrameData); i {
+			switch v := v.(*S2C_ProgressMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1046,7 +3092,7 @@ func file_message_proto_init() {
 			}
 		}
 		file_message_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*S2C_FrameMsg); i {
+			switch v := v.(*C2S_InputMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1058,6 +3104,138 @@ func file_message_proto_init() {
 			}
 		}
 		file_message_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_InputRejectMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InputData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FrameData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_FrameMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*C2S_HashMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_HashMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*C2S_SnapshotMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_SnapshotMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*C2S_KickMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_KickMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*C2S_ResultMsg); i {
 			case 0:
 				return &v.state
@@ -1069,6 +3247,102 @@ func file_message_proto_init() {
 				return nil
 			}
 		}
+		file_message_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_NetStateMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*C2S_PauseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_PauseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*C2S_ChatMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_ChatMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*C2S_RelayMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_RelayMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*S2C_CloseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_message_proto_msgTypes[0].OneofWrappers = []interface{}{}
 	file_message_proto_msgTypes[1].OneofWrappers = []interface{}{}
@@ -1079,14 +3353,34 @@ func file_message_proto_init() {
 	file_message_proto_msgTypes[6].OneofWrappers = []interface{}{}
 	file_message_proto_msgTypes[7].OneofWrappers = []interface{}{}
 	file_message_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[9].OneofWrappers = []interface{}{}
 	file_message_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[16].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[17].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[18].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[19].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[20].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[21].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[22].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[23].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[24].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[25].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[26].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[27].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[28].OneofWrappers = []interface{}{}
+	file_message_proto_msgTypes[29].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_message_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   11,
+			NumEnums:      6,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -125,6 +125,11 @@ func (c *Conn) AsyncWritePacket(p Packet, timeout time.Duration) (err error) {
 	}
 }
 
+// SendQueueLen returns the number of packets waiting to be written
+func (c *Conn) SendQueueLen() int {
+	return len(c.packetSendChan)
+}
+
 // Do it
 func (c *Conn) Do() {
 	if !c.callback.OnConnect(c) {
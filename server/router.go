@@ -66,6 +66,17 @@ func (r *LockStepServer) OnMessage(conn *network.Conn, p network.Packet) bool {
 			return true
 		}
 
+		if rec.GetSpectator() {
+			if token != room.SpectatorToken() {
+				ret.ErrorCode = pb.ERRORCODE_ERR_Token.Enum()
+				conn.AsyncWritePacket(pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), ret), time.Millisecond)
+				l4g.Error("[router] spectator token error player=[%d] room==[%d] token=[%s]", playerID, roomID, token)
+				return true
+			}
+
+			return room.OnSpectatorConnect(conn, playerID)
+		}
+
 		if !room.HasPlayer(playerID) {
 			ret.ErrorCode = pb.ERRORCODE_ERR_NoPlayer.Enum()
 			conn.AsyncWritePacket(pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), ret), time.Millisecond)
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/byebyebruce/lockstepserver/logic"
+	"github.com/byebyebruce/lockstepserver/pb"
+	"github.com/byebyebruce/lockstepserver/pkg/network"
+	"github.com/byebyebruce/lockstepserver/pkg/packet/pb_packet"
+	"github.com/golang/protobuf/proto"
+)
+
+func Test_SpectatorToken(t *testing.T) {
+	s := &LockStepServer{roomMgr: logic.NewRoomManager()}
+	defer s.roomMgr.Stop()
+
+	r, err := s.roomMgr.CreateRoom(1, 0, []uint64{1}, 0, "test")
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	// 用net.Pipe接一个真的连接，发观战的连接消息，返回服务端回的错误码
+	connect := func(token string) pb.ERRORCODE {
+		server, client := net.Pipe()
+		cfg := &network.Config{
+			PacketSendChanLimit:    16,
+			PacketReceiveChanLimit: 16,
+			ConnReadTimeout:        time.Minute,
+			ConnWriteTimeout:       time.Minute,
+		}
+		conn := network.NewConn(server, network.NewServer(cfg, s, &pb_packet.MsgProtocol{}))
+		conn.Do()
+		defer conn.Close()
+
+		s.OnMessage(conn, pb_packet.NewPacket(uint8(pb.ID_MSG_Connect), &pb.C2S_ConnectMsg{
+			PlayerID:  proto.Uint64(100),
+			BattleID:  proto.Uint64(1),
+			Token:     proto.String(token),
+			Spectator: proto.Bool(true),
+		}))
+
+		client.SetReadDeadline(time.Now().Add(time.Second))
+		p, err := (&pb_packet.MsgProtocol{}).ReadPacket(client)
+		if nil != err {
+			t.Fatalf("read packet error: %s", err.Error())
+		}
+		msg := &pb.S2C_ConnectMsg{}
+		if err := p.(*pb_packet.Packet).Unmarshal(msg); nil != err {
+			t.Fatal(err)
+		}
+		return msg.GetErrorCode()
+	}
+
+	// 令牌不对的不能观战
+	if code := connect("wrong"); code != pb.ERRORCODE_ERR_Token {
+		t.Errorf("wrong token should be rejected, got %v", code)
+	}
+	if code := connect(""); code != pb.ERRORCODE_ERR_Token {
+		t.Errorf("empty token should be rejected, got %v", code)
+	}
+	if code := connect(r.SpectatorToken()); code != pb.ERRORCODE_ERR_Ok {
+		t.Errorf("right token should join, got %v", code)
+	}
+}